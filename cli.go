@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"pomodoro-do-ben/config"
+)
+
+// runCommand handles the one-shot command-line actions (--export, --import).
+// It reports whether one was run, in which case the GUI is not started.
+func runCommand(cfg *config.Config, opts *config.Options, in io.Reader, out io.Writer) (bool, error) {
+	switch {
+	case opts.Export != "":
+		if err := cfg.Export(opts.Export); err != nil {
+			return true, err
+		}
+		fmt.Fprintln(out, "Settings exported to", opts.Export)
+		return true, nil
+
+	case opts.Import != "":
+		imported, err := cfg.ReadBundle(opts.Import)
+		if err != nil {
+			return true, err
+		}
+		saved, err := cfg.Saved()
+		if err != nil {
+			return true, err
+		}
+		changes := saved.Diff(imported)
+		if len(changes) == 0 {
+			fmt.Fprintln(out, "No changes: settings already match", opts.Import)
+			return true, nil
+		}
+		fmt.Fprintln(out, "Importing", opts.Import, "will change:")
+		for _, change := range changes {
+			fmt.Fprintln(out, "  "+change.String())
+		}
+		if !opts.AssumeYes {
+			fmt.Fprint(out, "Apply these changes? [y/N] ")
+			answer, _ := bufio.NewReader(in).ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if answer != "y" && answer != "yes" {
+				fmt.Fprintln(out, "Import cancelled.")
+				return true, nil
+			}
+		}
+		if err := cfg.Import(imported); err != nil {
+			return true, err
+		}
+		if err := cfg.Save(); err != nil {
+			return true, err
+		}
+		fmt.Fprintln(out, "Settings imported.")
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Phases a bell can ring in.
+const (
+	PhaseFocus      = "focus"
+	PhaseShortBreak = "short_break"
+	PhaseLongBreak  = "long_break"
+)
+
+// Phases lists every phase in the order they are shown in Settings.
+var Phases = []string{PhaseFocus, PhaseShortBreak, PhaseLongBreak}
+
+// Bell rings inside a phase: once at a point of it, or over and over.
+//
+// The first ring is at Fraction of the phase if set, else At after its start,
+// or -At before its end when At is negative. With Every set, the bell then
+// rings every Every until the phase ends; without a first point it starts
+// after Every. Bells never ring at the very start or end of a phase, which
+// have sounds of their own.
+type Bell struct {
+	Phase    string        `json:"phase"`
+	At       time.Duration `json:"at,omitempty"`
+	Fraction float64       `json:"fraction,omitempty"` // e.g. 0.5 for halfway
+	Every    time.Duration `json:"every,omitempty"`
+	Sound    string        `json:"sound"` // as in Config.Sounds
+	Notify   bool          `json:"notify"`
+}
+
+// Due reports whether the bell rings at elapsed into a phase of the given
+// length. The timer counts whole seconds, so a ring is due during the second
+// that starts at its time.
+func (b Bell) Due(length, elapsed time.Duration) bool {
+	if elapsed <= 0 || elapsed >= length {
+		return false
+	}
+	first := b.At
+	switch {
+	case b.Fraction > 0:
+		first = time.Duration(b.Fraction * float64(length))
+	case b.At < 0:
+		first = length + b.At
+	case b.At == 0:
+		first = b.Every
+	}
+	if first <= 0 || elapsed < first {
+		return false
+	}
+	since := elapsed - first
+	if b.Every >= time.Second {
+		since %= b.Every
+	} else if b.Every > 0 {
+		return false
+	}
+	return since < time.Second
+}
+
+// When returns the first point of the bell as ParseBellWhen reads it: "50%",
+// "10m" or "-1m".
+func (b Bell) When() string {
+	switch {
+	case b.Fraction > 0:
+		return strconv.FormatFloat(b.Fraction*100, 'f', -1, 64) + "%"
+	case b.At == 0:
+		return ""
+	}
+	return formatShortDuration(b.At)
+}
+
+// SetWhen sets the first point of the bell from text such as "50%" (halfway
+// through), "10m" (after ten minutes) or "-1m" (a minute before the end).
+// Empty text clears it.
+func (b *Bell) SetWhen(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		b.At, b.Fraction = 0, 0
+		return nil
+	}
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || f <= 0 || f >= 100 {
+			return fmt.Errorf("invalid bell time %q", s)
+		}
+		b.At, b.Fraction = 0, f/100
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d == 0 {
+		return fmt.Errorf("invalid bell time %q", s)
+	}
+	b.At, b.Fraction = d, 0
+	return nil
+}
+
+// Repeat returns Every as SetRepeat reads it, or "" if the bell rings once.
+func (b Bell) Repeat() string {
+	if b.Every == 0 {
+		return ""
+	}
+	return formatShortDuration(b.Every)
+}
+
+// SetRepeat sets Every from text such as "10m". Empty text makes the bell
+// ring once.
+func (b *Bell) SetRepeat(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		b.Every = 0
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return fmt.Errorf("invalid bell interval %q", s)
+	}
+	b.Every = d
+	return nil
+}
+
+// formatShortDuration formats d without the zero units time.Duration.String
+// adds, e.g. "10m" instead of "10m0s".
+func formatShortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// BellsIn returns the bells of phase.
+func (c *Config) BellsIn(phase string) []Bell {
+	var bells []Bell
+	for _, b := range c.Bells {
+		if b.Phase == phase {
+			bells = append(bells, b)
+		}
+	}
+	return bells
+}
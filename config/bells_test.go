@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBellDue(t *testing.T) {
+	const length = 25 * time.Minute
+	tests := []struct {
+		name  string
+		when  string
+		every string
+		rings []time.Duration
+	}{
+		{"halfway", "50%", "", []time.Duration{12*time.Minute + 30*time.Second}},
+		{"after", "10m", "", []time.Duration{10 * time.Minute}},
+		{"before the end", "-1m", "", []time.Duration{24 * time.Minute}},
+		{"every", "", "10m", []time.Duration{10 * time.Minute, 20 * time.Minute}},
+		{"every from a point", "5m", "10m", []time.Duration{5 * time.Minute, 15 * time.Minute}},
+		{"not at the end", "", "5m", []time.Duration{5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 20 * time.Minute}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Bell
+			if err := b.SetWhen(tt.when); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.SetRepeat(tt.every); err != nil {
+				t.Fatal(err)
+			}
+			if b.When() != tt.when || b.Repeat() != tt.every {
+				t.Errorf("Expected %q every %q to read back, got %q every %q", tt.when, tt.every, b.When(), b.Repeat())
+			}
+			var rings []time.Duration
+			for elapsed := time.Duration(0); elapsed <= length; elapsed += time.Second {
+				if b.Due(length, elapsed) {
+					rings = append(rings, elapsed)
+				}
+			}
+			if len(rings) != len(tt.rings) {
+				t.Fatalf("Expected rings at %v, got %v", tt.rings, rings)
+			}
+			for i := range rings {
+				if rings[i] != tt.rings[i] {
+					t.Errorf("Expected rings at %v, got %v", tt.rings, rings)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestBellSetWhenRejects(t *testing.T) {
+	for _, s := range []string{"0%", "100%", "soon", "0s"} {
+		var b Bell
+		if err := b.SetWhen(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const bundleVersion = 1
+
+// bundle is the file format used to share a complete setup between machines.
+type bundle struct {
+	App      string          `json:"app"`
+	Version  int             `json:"version"`
+	Settings json.RawMessage `json:"settings"`
+}
+
+// Change describes one setting that an import would modify.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+func (ch Change) String() string {
+	return fmt.Sprintf("%s: %s → %s", ch.Key, ch.Old, ch.New)
+}
+
+// privateKeys are settings about the user rather than the setup, which
+// Export leaves out.
+var privateKeys = []string{"current_task"}
+
+// Export writes the saved settings (without launch-time overrides) to a
+// single bundle file, so it can be shared. Webhook headers, which often hold
+// credentials, and the privateKeys are left out.
+func (c *Config) Export(path string) error {
+	settings, err := c.encode()
+	if err != nil {
+		return err
+	}
+	if settings, err = shareable(settings); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bundle{App: DirName, Version: bundleVersion, Settings: settings}); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// shareable removes what Export leaves out from settings, a config as JSON.
+func shareable(settings []byte) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(settings, &m); err != nil {
+		return nil, err
+	}
+	for _, key := range privateKeys {
+		delete(m, key)
+	}
+	if raw, ok := m["webhooks"]; ok {
+		var hooks []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &hooks); err != nil {
+			return nil, err
+		}
+		for _, hook := range hooks {
+			delete(hook, "headers")
+		}
+		data, err := json.Marshal(hooks)
+		if err != nil {
+			return nil, err
+		}
+		m["webhooks"] = data
+	}
+	return json.Marshal(m)
+}
+
+// ReadBundle decodes a bundle written by Export on top of the saved settings
+// (see Saved), so settings missing from the file keep their saved values.
+// Imported webhooks without headers get those of the saved webhook with the
+// same URL. A plain config.json is accepted as well.
+func (c *Config) ReadBundle(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := data
+	var b bundle
+	if err := json.Unmarshal(data, &b); err == nil && b.App != "" {
+		if b.App != DirName {
+			return nil, fmt.Errorf("%s is not a %s settings file", path, AppName)
+		}
+		if b.Version > bundleVersion {
+			return nil, fmt.Errorf("%s was exported by a newer version (format %d)", path, b.Version)
+		}
+		settings = b.Settings
+	}
+
+	saved, err := c.Saved()
+	if err != nil {
+		return nil, err
+	}
+	imported := saved.clone()
+	imported.Profiles, imported.Webhooks = nil, nil
+	if err := json.Unmarshal(settings, imported); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if imported.Profiles == nil {
+		imported.Profiles = saved.Profiles
+	}
+	if imported.Webhooks == nil {
+		imported.Webhooks = saved.Webhooks
+	}
+	for i, hook := range imported.Webhooks {
+		for _, own := range saved.Webhooks {
+			if hook.Headers == nil && own.URL == hook.URL {
+				imported.Webhooks[i].Headers = own.Headers
+				break
+			}
+		}
+	}
+	return imported, nil
+}
+
+// Diff lists the settings that differ between c and other.
+func (c *Config) Diff(other *Config) []Change {
+	var changes []Change
+	a, b := reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		key := jsonKey(field)
+		if key == "" {
+			continue
+		}
+		oldValue, newValue := a.Field(i).Interface(), b.Field(i).Interface()
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, Change{Key: key, Old: formatValue(oldValue), New: formatValue(newValue)})
+	}
+	return changes
+}
+
+// Saved returns the settings as Save writes them: the current ones, with the
+// values that still hold a launch-time override set back to the file's.
+func (c *Config) Saved() (*Config, error) {
+	active, err := c.activeOverrides()
+	if err != nil {
+		return nil, err
+	}
+	saved := c.clone()
+	saved.base, saved.overridden, saved.launch = nil, nil, nil
+	if len(active) == 0 {
+		return saved, nil
+	}
+	base, err := toMap(c.base)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]json.RawMessage{}
+	for key := range active {
+		values[key] = base[key]
+	}
+	return saved, setValues(saved, values)
+}
+
+// Import replaces the saved settings with those of other. Values overridden
+// at launch and not changed since stay in effect for this run, on top of the
+// imported ones, and are still kept out of the file.
+func (c *Config) Import(other *Config) error {
+	active, err := c.activeOverrides()
+	if err != nil {
+		return err
+	}
+	imported := other.clone()
+	imported.path, imported.paths = c.path, c.paths
+	imported.base = other.clone()
+	imported.overridden, imported.launch = nil, nil
+	if len(active) > 0 {
+		imported.overridden = active
+		imported.launch = map[string]json.RawMessage{}
+		for key := range active {
+			imported.launch[key] = c.launch[key]
+		}
+		if err := setValues(imported, imported.launch); err != nil {
+			return err
+		}
+	}
+	*c = *imported
+	return nil
+}
+
+// setValues decodes the given JSON values into c by key.
+func setValues(c *Config, values map[string]json.RawMessage) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
+}
+
+func (c *Config) clone() *Config {
+	dup := *c
+	if c.Profiles != nil {
+		dup.Profiles = make(map[string]Profile, len(c.Profiles))
+		for name, p := range c.Profiles {
+			dup.Profiles[name] = p
+		}
+	}
+	if c.Sounds != nil {
+		dup.Sounds = make(map[string]string, len(c.Sounds))
+		for event, sound := range c.Sounds {
+			dup.Sounds[event] = sound
+		}
+	}
+	if c.Messages != nil {
+		dup.Messages = make(map[string]Message, len(c.Messages))
+		for event, m := range c.Messages {
+			dup.Messages[event] = m
+		}
+	}
+	if c.Bells != nil {
+		dup.Bells = append([]Bell(nil), c.Bells...)
+	}
+	if c.Warnings != nil {
+		dup.Warnings = append([]Warning(nil), c.Warnings...)
+	}
+	if c.Webhooks != nil {
+		dup.Webhooks = make([]Webhook, len(c.Webhooks))
+		for i, w := range c.Webhooks {
+			w.Events = append([]string(nil), w.Events...)
+			if w.Headers != nil {
+				headers := make(map[string]string, len(w.Headers))
+				for name, value := range w.Headers {
+					headers[name] = value
+				}
+				w.Headers = headers
+			}
+			dup.Webhooks[i] = w
+		}
+	}
+	return &dup
+}
+
+func jsonKey(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case time.Duration, bool, int:
+		return fmt.Sprint(v)
+	}
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := defaults()
+	source.path = filepath.Join(dir, "source.json")
+	source.FocusDuration = 45 * time.Minute
+	source.Animation = "slideshow"
+	source.Profiles = map[string]Profile{"deep": {FocusDuration: 90 * time.Minute}}
+
+	bundlePath := filepath.Join(dir, "team.json")
+	if err := source.Export(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+
+	target := defaults()
+	target.path = filepath.Join(dir, "target.json")
+	imported, err := target.ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := map[string]bool{}
+	for _, change := range target.Diff(imported) {
+		changed[change.Key] = true
+	}
+	for _, key := range []string{"focus_duration", "animation", "profiles"} {
+		if !changed[key] {
+			t.Errorf("Expected %s in the import preview, got %v", key, changed)
+		}
+	}
+	if len(changed) != 3 {
+		t.Errorf("Expected exactly 3 changes, got %v", changed)
+	}
+
+	if err := target.Import(imported); err != nil {
+		t.Fatal(err)
+	}
+	if target.FocusDuration != 45*time.Minute || target.Profiles["deep"].FocusDuration != 90*time.Minute {
+		t.Errorf("Expected imported values, got %+v", target)
+	}
+	if target.path != filepath.Join(dir, "target.json") {
+		t.Errorf("Expected import to keep the target file, got %s", target.path)
+	}
+}
+
+func TestImportKeepsLaunchOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"focus_duration": 1500000000000, "animation": "icons"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := filepath.Join(dir, "team.json")
+	source := defaults()
+	source.FocusDuration = 30 * time.Minute
+	source.Animation = "slideshow"
+	if err := source.Export(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := ParseOptions([]string{"--config", path, "--focus", "50m"}, nil, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := cfg.ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := cfg.Saved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range saved.Diff(imported) {
+		if change.Key == "focus_duration" && change.Old != "25m0s" {
+			t.Errorf("Expected the preview to start from the saved 25m, got %v", change)
+		}
+	}
+
+	if err := cfg.Import(imported); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FocusDuration != 50*time.Minute || cfg.Animation != "slideshow" {
+		t.Errorf("Expected the launch override on top of the import, got %v, %q", cfg.FocusDuration, cfg.Animation)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadWithOptions(&Options{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.FocusDuration != 30*time.Minute || reloaded.Animation != "slideshow" {
+		t.Errorf("Expected the imported settings saved without the override, got %v, %q", reloaded.FocusDuration, reloaded.Animation)
+	}
+}
+
+func TestExportLeavesOutPrivateSettings(t *testing.T) {
+	dir := t.TempDir()
+	source := defaults()
+	source.path = filepath.Join(dir, "source.json")
+	source.CurrentTask = "Quarterly review"
+	source.Webhooks = []Webhook{
+		{URL: "https://chat.example.com/status", Headers: map[string]string{"Authorization": "Bearer source-token"}},
+		{URL: "https://lights.example.com/", Headers: map[string]string{"X-Key": "source-key"}},
+	}
+	bundlePath := filepath.Join(dir, "team.json")
+	if err := source.Export(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, private := range []string{"source-token", "source-key", "current_task", "Quarterly review"} {
+		if strings.Contains(string(data), private) {
+			t.Errorf("Expected %q left out of the bundle, got:\n%s", private, data)
+		}
+	}
+
+	target := defaults()
+	target.path = filepath.Join(dir, "target.json")
+	target.CurrentTask = "Inbox"
+	target.Webhooks = []Webhook{
+		{URL: "https://lights.example.com/", Headers: map[string]string{"X-Key": "target-key"}},
+	}
+	imported, err := target.ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.CurrentTask != "Inbox" {
+		t.Errorf("Expected the current task kept, got %q", imported.CurrentTask)
+	}
+	if len(imported.Webhooks) != 2 || imported.Webhooks[0].Headers != nil || imported.Webhooks[1].Headers["X-Key"] != "target-key" {
+		t.Errorf("Expected only the target's own headers on its webhook, got %+v", imported.Webhooks)
+	}
+}
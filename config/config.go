@@ -1,8 +1,9 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
-	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"time"
@@ -27,10 +28,96 @@ type Config struct {
 	ShortBreakDuration time.Duration `json:"short_break_duration"`
 	LongBreakDuration  time.Duration `json:"long_break_duration"`
 	LongBreakInterval  int           `json:"long_break_interval"`
+	MasterVolume       float64       `json:"master_volume"`
+	CueVolume          float64       `json:"cue_volume"`
+	AmbienceVolume     float64       `json:"ambience_volume"`
+	MeditationVolume   float64       `json:"meditation_volume"`
+
+	// Meditation is a synthesized binaural beat: a Carrier Hz tone with the
+	// ears detuned by Beat Hz.
+	MeditationCarrier      float64       `json:"meditation_carrier"`
+	MeditationBeat         float64       `json:"meditation_beat"`
+	MeditationDuration     time.Duration `json:"meditation_duration"`
+	MeditationFadeDuration time.Duration `json:"meditation_fade_duration"`
+
+	// FocusNoise is the generated noise played during focus: "off", "white",
+	// "pink", "brown" or "rain". It stops during breaks unless
+	// FocusNoiseInBreaks keeps it going quietly.
+	FocusNoise         string `json:"focus_noise"`
+	FocusNoiseInBreaks bool   `json:"focus_noise_in_breaks"`
+
+	// Ticking clock during focus. TickStyle is "tick" or "ticktock".
+	TickEnabled        bool    `json:"tick_enabled"`
+	TickStyle          string  `json:"tick_style"`
+	TickVolume         float64 `json:"tick_volume"`
+	TickLastMinuteOnly bool    `json:"tick_last_minute_only"`
+
+	// Spoken announcements of phase changes. SpeechVoice is passed to the
+	// speech engine; empty uses the UI language.
+	SpeechEnabled bool   `json:"speech_enabled"`
+	SpeechRate    int    `json:"speech_rate"`
+	SpeechVoice   string `json:"speech_voice"`
+
+	// PlaylistDir is a folder of audio files played as ambience during focus.
+	PlaylistDir               string        `json:"playlist_dir"`
+	PlaylistShuffle           bool          `json:"playlist_shuffle"`
+	PlaylistLoop              bool          `json:"playlist_loop"`
+	PlaylistCrossfadeDuration time.Duration `json:"playlist_crossfade_duration"`
+
+	// AudioOutput is where sound goes: "device", or for testing "null"
+	// (silent) or "record" (silent, logging every sound that plays).
+	AudioOutput string `json:"audio_output"`
+
+	// Notifier shows notifications: "auto", "dbus", "notify-send", "fyne",
+	// "stdout" or "none". Unavailable ones fall back to the automatic choice.
+	Notifier string `json:"notifier"`
+
+	// Messages overrides the text of notifications, by event (see
+	// MessageEvents).
+	Messages map[string]Message `json:"messages,omitempty"`
+
+	// CurrentTask is what the user is working on, for the {task} placeholder.
+	CurrentTask string `json:"current_task"`
+
+	// CountdownNotification keeps one notification up while the timer runs,
+	// showing the phase and the time left.
+	CountdownNotification bool `json:"countdown_notification"`
+
+	// Sounds maps events (see Events) to the sound played for them: a file
+	// path, a built-in sound or SoundNone.
+	Sounds map[string]string `json:"sounds,omitempty"`
+
+	// Bells ring at points inside a phase; see Bell.
+	Bells []Bell `json:"bells,omitempty"`
+
+	// Warnings give heads-ups before a phase ends; see Warning.
+	Warnings []Warning `json:"warnings"`
+
+	// Webhooks are POSTed to on timer events; see Webhook.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+
+	// Profiles are named sets of durations that can be selected at launch
+	// with --profile or POMODORO_PROFILE.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	path       string                     // file this config was loaded from and saves to
+	paths      Paths                      // directories in use for this run
+	base       *Config                    // values as read from the file, before overrides
+	overridden map[string]bool            // JSON keys replaced by a profile, env or flag
+	launch     map[string]json.RawMessage // overridden values as they were at launch
 }
 
-func Load() (*Config, error) {
-	cfg := &Config{
+// Profile overrides the timer durations of the base config. Zero fields keep
+// the base value.
+type Profile struct {
+	FocusDuration      time.Duration `json:"focus_duration,omitempty"`
+	ShortBreakDuration time.Duration `json:"short_break_duration,omitempty"`
+	LongBreakDuration  time.Duration `json:"long_break_duration,omitempty"`
+	LongBreakInterval  int           `json:"long_break_interval,omitempty"`
+}
+
+func defaults() *Config {
+	return &Config{
 		StartOnLaunch:      true,
 		AutoStartCycles:    true,
 		Slideshow:          false,
@@ -45,56 +132,253 @@ func Load() (*Config, error) {
 		ShortBreakDuration: 5 * time.Minute,
 		LongBreakDuration:  15 * time.Minute,
 		LongBreakInterval:  4, // Default to 4 pomodoros for a long break
+		MasterVolume:       1,
+		CueVolume:          1,
+		AmbienceVolume:     0.5,
+		MeditationVolume:   0.8,
+
+		MeditationCarrier:      200,
+		MeditationBeat:         6, // theta
+		MeditationDuration:     5 * time.Minute,
+		MeditationFadeDuration: 10 * time.Second,
+
+		FocusNoise: "off",
+
+		TickStyle:  "ticktock",
+		TickVolume: 0.5,
+
+		SpeechRate: 160,
+
+		PlaylistLoop:              true,
+		PlaylistCrossfadeDuration: 3 * time.Second,
+
+		AudioOutput: "device",
+		Notifier:    "auto",
+
+		Sounds: map[string]string{
+			EventFocusStart:      BuiltinSoundPrefix + "focus",
+			EventBreakStart:      BuiltinSoundPrefix + "break",
+			EventLongBreakStart:  BuiltinSoundPrefix + "break",
+			EventPause:           SoundNone,
+			EventWarning:         SoundNone,
+			EventSessionComplete: BuiltinSoundPrefix + "break",
+		},
+		Warnings: []Warning{},
 	}
+}
+
+func Load() (*Config, error) {
+	return LoadWithOptions(&Options{})
+}
 
-	path, err := configPath()
+// readConfig loads the file at path, falling back to its backup when the file
+// itself is unreadable.
+func readConfig(path string) (*Config, error) {
+	cfg, err := loadFile(path, formatOf(path))
 	if err != nil {
-		return nil, err
+		// The main file is unreadable or corrupt; fall back to the last-known-good copy.
+		backup, backupErr := loadFile(backupPath(path), formatOf(path))
+		if backupErr != nil {
+			return nil, err
+		}
+		log.Println("Config file is unreadable, restored from backup:", err)
+		cfg = backup
 	}
 
-	file, err := os.Open(path)
+	cfg.path = path
+	return cfg, nil
+}
+
+// loadFile decodes the config at path, written in format f, on top of the
+// defaults. A missing or empty file yields the defaults.
+func loadFile(path string, f format) (*Config, error) {
+	cfg := defaults()
+
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return cfg, nil
 		}
 		return nil, err
 	}
-	defer file.Close()
 
-	if err := json.NewDecoder(file).Decode(cfg); err != nil {
-		// An empty file will result in an EOF error, which we can safely ignore.
-		if err == io.EOF {
-			return cfg, nil // Return default config if file is empty
-		}
-		return nil, err // Return other errors
+	// An empty file is treated like a missing one.
+	if len(bytes.TrimSpace(data)) == 0 {
+		return cfg, nil
+	}
+	if err := decodeConfig(f, data, cfg); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// Save writes the config atomically: the data goes to a temporary file that is
+// synced and then renamed over the config file, so a crash never leaves a
+// truncated file behind. The previous file is kept with a .bak suffix. The
+// file keeps its format (JSON, TOML or YAML).
 func (c *Config) Save() error {
-	path, err := configPath()
+	data, err := c.encode()
 	if err != nil {
 		return err
 	}
+	path, err := c.filePath()
+	if err != nil {
+		return err
+	}
+	return writeConfig(path, data)
+}
 
+// encode serializes the config for saving. Values that still hold their
+// launch-time override are replaced by what the file had, so a one-off
+// override never ends up in the user's saved settings. Values changed since,
+// e.g. in Settings, are saved.
+func (c *Config) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	active, err := c.activeOverrides()
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		if err := json.NewEncoder(&buf).Encode(c); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	effective, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+	base, err := toMap(c.base)
+	if err != nil {
+		return nil, err
+	}
+	for key := range active {
+		if value, ok := base[key]; ok {
+			effective[key] = value
+		} else {
+			delete(effective, key)
+		}
+	}
+	if err := json.NewEncoder(&buf).Encode(effective); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// activeOverrides returns the overridden keys that still hold their
+// launch-time value.
+func (c *Config) activeOverrides() (map[string]bool, error) {
+	if len(c.overridden) == 0 || c.base == nil {
+		return nil, nil
+	}
+	effective, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+	active := map[string]bool{}
+	for key := range c.overridden {
+		if bytes.Equal(effective[key], c.launch[key]) {
+			active[key] = true
+		}
+	}
+	return active, nil
+}
+
+func toMap(c *Config) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// filePath returns the file the config is saved to.
+func (c *Config) filePath() (string, error) {
+	if c.path != "" {
+		return c.path, nil
+	}
+	return configPath()
+}
+
+func writeConfig(path string, data []byte) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
 
-	file, err := os.Create(path)
+	f := formatOf(path)
+	current, err := os.ReadFile(path)
+	if err != nil {
+		current = nil
+	}
+	// Only a file that still decodes is worth keeping as a backup.
+	if len(current) > 0 && decodeConfig(f, current, defaults()) == nil {
+		if err := writeFileAtomic(backupPath(path), current); err != nil {
+			return err
+		}
+	}
+
+	data, err = convertConfig(f, data, current)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	return writeFileAtomic(path, data)
+}
 
-	return json.NewEncoder(file).Encode(c)
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	// Persist the rename itself. Not every platform can sync a directory,
+	// so failures here are ignored.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// Paths returns the settings and data directories in use.
+func (c *Config) Paths() Paths {
+	return c.paths
 }
 
 func configPath() (string, error) {
-	dir, err := os.UserConfigDir()
+	paths, err := resolvePaths(false)
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dir, AppName, "config.json"), nil
+	return findConfigFile(paths.ConfigDir), nil
+}
+
+func backupPath(path string) string {
+	return path + ".bak"
 }
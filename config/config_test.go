@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"focus_duration": 3000000000000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.FocusDuration = 40 * time.Minute
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.FocusDuration != 40*time.Minute {
+		t.Errorf("Expected the new value to be saved, got %v", saved.FocusDuration)
+	}
+	backup, err := loadFile(backupPath(path), formatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup.FocusDuration != 50*time.Minute {
+		t.Errorf("Expected the previous file as backup, got %v", backup.FocusDuration)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("Expected no temporary file left behind, found %s", e.Name())
+		}
+	}
+}
+
+func TestLoadRestoresBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	good := []byte(`{"focus_duration": 3000000000000}`)
+	if err := os.WriteFile(path, []byte(`{"focus_duration": 30`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(backupPath(path), good, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FocusDuration != 50*time.Minute {
+		t.Errorf("Expected the backup to be loaded, got %v", cfg.FocusDuration)
+	}
+
+	// Saving over the corrupt file must not replace the good backup with it.
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(backupPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != string(good) {
+		t.Errorf("Expected the backup to be kept, got %s", backup)
+	}
+	if _, err := readConfig(path); err != nil {
+		t.Errorf("Expected the saved file to load: %v", err)
+	}
+}
+
+func TestLoadFailsWithoutGoodBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(backupPath(path), []byte(`not json`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readConfig(path); err == nil {
+		t.Error("Expected an error when the file and its backup are corrupt")
+	}
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// format is the syntax of a config file, chosen by its extension.
+type format int
+
+const (
+	formatJSON format = iota
+	formatTOML
+	formatYAML
+)
+
+// configFileNames are the names looked for in the config directory, in order
+// of preference. JSON is last so that a hand-written TOML or YAML file wins
+// over the one the app created on first run.
+var configFileNames = []string{"config.toml", "config.yaml", "config.yml", configFileName}
+
+func formatOf(path string) format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return formatTOML
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatJSON
+}
+
+// findConfigFile returns the config file to use in dir: the first existing
+// one of configFileNames, or config.json if there is none yet.
+func findConfigFile(dir string) string {
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, configFileName)
+}
+
+// decodeConfig parses data in the given format on top of cfg. TOML and YAML
+// go through the same JSON mapping so that keys are identical in every
+// format; durations there may be written as strings like "25m".
+func decodeConfig(f format, data []byte, cfg *Config) error {
+	jsonData := data
+	if f != formatJSON {
+		m := map[string]any{}
+		var err error
+		if f == formatTOML {
+			err = toml.Unmarshal(data, &m)
+		} else {
+			err = yaml.Unmarshal(data, &m)
+		}
+		if err != nil {
+			return err
+		}
+		if err := parseDurations(m); err != nil {
+			return err
+		}
+		if jsonData, err = json.Marshal(m); err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(jsonData, cfg); err != nil {
+		return err
+	}
+	return upgradeWarnings(jsonData, cfg)
+}
+
+// convertConfig turns the JSON produced by Config.encode into the given
+// format, keeping the key order and comments of previous, the file being
+// replaced.
+func convertConfig(f format, jsonData []byte, previous []byte) ([]byte, error) {
+	if f == formatJSON {
+		return jsonData, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	m := map[string]any{}
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	formatDurations(m)
+
+	var buf bytes.Buffer
+	if f == formatTOML {
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+		if len(previous) > 0 {
+			return keepTOMLLayout(previous, buf.Bytes()), nil
+		}
+		return buf.Bytes(), nil
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(m); err != nil {
+		return nil, err
+	}
+	var old yaml.Node
+	if len(previous) > 0 && yaml.Unmarshal(previous, &old) == nil && len(old.Content) > 0 {
+		keepLayout(old.Content[0], &doc)
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// keepLayout carries the key order and comments of old over to updated, so
+// a hand-edited YAML file keeps its layout. Keys only in updated go last.
+// Both must be YAML mapping nodes.
+func keepLayout(old, updated *yaml.Node) {
+	if old.Kind != yaml.MappingNode || updated.Kind != yaml.MappingNode {
+		return
+	}
+	updated.HeadComment, updated.FootComment = old.HeadComment, old.FootComment
+
+	content := make([]*yaml.Node, 0, len(updated.Content))
+	used := make([]bool, len(updated.Content)/2)
+	for i := 0; i+1 < len(old.Content); i += 2 {
+		oldKey, oldValue := old.Content[i], old.Content[i+1]
+		for j := 0; j+1 < len(updated.Content); j += 2 {
+			key, value := updated.Content[j], updated.Content[j+1]
+			if used[j/2] || key.Value != oldKey.Value {
+				continue
+			}
+			key.HeadComment, key.LineComment, key.FootComment = oldKey.HeadComment, oldKey.LineComment, oldKey.FootComment
+			value.LineComment = oldValue.LineComment
+			keepLayout(oldValue, value)
+			content = append(content, key, value)
+			used[j/2] = true
+		}
+	}
+	for j := 0; j+1 < len(updated.Content); j += 2 {
+		if !used[j/2] {
+			content = append(content, updated.Content[j], updated.Content[j+1])
+		}
+	}
+	updated.Content = content
+}
+
+// durationKeys are the JSON keys of every time.Duration in a Config, at any
+// depth: "focus_duration", a bell's "at" and "every", a warning's "before"...
+var durationKeys = collectDurationKeys(reflect.TypeOf(Config{}), map[string]bool{})
+
+func collectDurationKeys(t reflect.Type, keys map[string]bool) map[string]bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := jsonKey(field)
+		if key == "" {
+			continue
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map || ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft == reflect.TypeOf(time.Duration(0)):
+			keys[key] = true
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}):
+			collectDurationKeys(ft, keys)
+		}
+	}
+	return keys
+}
+
+// parseDurations converts durations (see durationKeys) written as strings
+// ("25m") to nanoseconds, recursing into nested tables such as profiles and
+// lists such as bells.
+func parseDurations(m map[string]any) error {
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := parseDurations(v); err != nil {
+				return err
+			}
+		case []any:
+			for _, item := range v {
+				if nested, ok := item.(map[string]any); ok {
+					if err := parseDurations(nested); err != nil {
+						return err
+					}
+				}
+			}
+		case []map[string]any: // TOML arrays of tables
+			for _, nested := range v {
+				if err := parseDurations(nested); err != nil {
+					return err
+				}
+			}
+		case string:
+			if durationKeys[key] {
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+				m[key] = int64(d)
+			}
+		}
+	}
+	return nil
+}
+
+// formatDurations is the inverse of parseDurations. Other numbers are turned
+// into plain ints or floats, which both encoders understand.
+func formatDurations(m map[string]any) {
+	for key, value := range m {
+		switch v := value.(type) {
+		case map[string]any:
+			formatDurations(v)
+		case []any:
+			for _, item := range v {
+				if nested, ok := item.(map[string]any); ok {
+					formatDurations(nested)
+				}
+			}
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				if durationKeys[key] {
+					m[key] = time.Duration(n).String()
+				} else {
+					m[key] = n
+				}
+			} else if f, err := v.Float64(); err == nil {
+				m[key] = f
+			}
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadAndSaveKeepFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		saved    string // expected in the file after Save
+	}{
+		{
+			name: "toml",
+			file: "config.toml",
+			contents: `# Team defaults
+focus_duration = "50m" # longer focus
+animation = "slideshow"
+
+[profiles.deep]
+focus_duration = "90m"
+`,
+			saved: `focus_duration = "45m0s" # longer focus`,
+		},
+		{
+			name: "yaml",
+			file: "config.yaml",
+			contents: `# Team defaults
+focus_duration: 50m # longer focus
+animation: slideshow
+profiles:
+  deep:
+    focus_duration: 90m
+`,
+			saved: "focus_duration: 45m0s # longer focus",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"focus_duration": 60000000000}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := findConfigFile(dir); got != path {
+				t.Fatalf("Expected %s to be preferred over config.json, got %s", tt.file, got)
+			}
+			cfg, err := LoadWithOptions(&Options{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.FocusDuration != 50*time.Minute || cfg.Animation != "slideshow" || cfg.Profiles["deep"].FocusDuration != 90*time.Minute {
+				t.Fatalf("Unexpected config: %+v", cfg)
+			}
+			if cfg.LongBreakInterval != 4 {
+				t.Errorf("Expected defaults for missing keys, got interval %d", cfg.LongBreakInterval)
+			}
+
+			cfg.FocusDuration = 45 * time.Minute
+			if err := cfg.Save(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.saved) {
+				t.Errorf("Expected saved file to contain %q, got:\n%s", tt.saved, data)
+			}
+
+			reloaded, err := LoadWithOptions(&Options{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reloaded.FocusDuration != 45*time.Minute || reloaded.Profiles["deep"].FocusDuration != 90*time.Minute {
+				t.Errorf("Unexpected config after reload: %+v", reloaded)
+			}
+		})
+	}
+}
+
+func TestBellDurationsRoundTrip(t *testing.T) {
+	tests := []struct {
+		file     string
+		contents string
+		saved    []string // expected in the file after Save
+	}{
+		{
+			file: "config.toml",
+			contents: `[[bells]]
+phase = "focus"
+at = "-1m"
+sound = "builtin:focus"
+
+[[bells]]
+phase = "long_break"
+every = "10m"
+sound = "builtin:break"
+`,
+			saved: []string{`at = "-1m0s"`, `every = "10m0s"`},
+		},
+		{
+			file: "config.yaml",
+			contents: `bells:
+  - phase: focus
+    at: -1m
+    sound: builtin:focus
+  - phase: long_break
+    every: 10m
+    sound: builtin:break
+`,
+			saved: []string{"at: -1m0s", "every: 10m0s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadWithOptions(&Options{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []Bell{
+				{Phase: PhaseFocus, At: -time.Minute, Sound: "builtin:focus"},
+				{Phase: PhaseLongBreak, Every: 10 * time.Minute, Sound: "builtin:break"},
+			}
+			if len(cfg.Bells) != 2 || cfg.Bells[0] != want[0] || cfg.Bells[1] != want[1] {
+				t.Fatalf("Expected bells %+v, got %+v", want, cfg.Bells)
+			}
+
+			if err := cfg.Save(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.saved {
+				if !strings.Contains(string(data), s) {
+					t.Errorf("Expected saved file to contain %q, got:\n%s", s, data)
+				}
+			}
+			reloaded, err := loadFile(path, formatOf(path)) // not the backup
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reloaded.Bells) != 2 || reloaded.Bells[0] != want[0] || reloaded.Bells[1] != want[1] {
+				t.Errorf("Expected bells %+v after reload, got %+v", want, reloaded.Bells)
+			}
+		})
+	}
+}
+
+func TestWarningDurationsRoundTrip(t *testing.T) {
+	tests := []struct {
+		file     string
+		contents string
+		saved    []string // expected in the file after Save
+	}{
+		{
+			file: "config.toml",
+			contents: `[[warnings]]
+phase = "focus"
+before = "2m"
+notify = true
+
+[[warnings]]
+phase = "focus"
+before = "30s"
+sound = true
+`,
+			saved: []string{`before = "2m0s"`, `before = "30s"`},
+		},
+		{
+			file: "config.yaml",
+			contents: `warnings:
+  - phase: focus
+    before: 2m
+    notify: true
+  - phase: focus
+    before: 30s
+    sound: true
+`,
+			saved: []string{"before: 2m0s", "before: 30s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadWithOptions(&Options{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []Warning{
+				{Phase: PhaseFocus, Before: 2 * time.Minute, Notify: true},
+				{Phase: PhaseFocus, Before: 30 * time.Second, Sound: true},
+			}
+			if len(cfg.Warnings) != 2 || cfg.Warnings[0] != want[0] || cfg.Warnings[1] != want[1] {
+				t.Fatalf("Expected warnings %+v, got %+v", want, cfg.Warnings)
+			}
+
+			if err := cfg.Save(); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.saved {
+				if !strings.Contains(string(data), s) {
+					t.Errorf("Expected saved file to contain %q, got:\n%s", s, data)
+				}
+			}
+			reloaded, err := loadFile(path, formatOf(path)) // not the backup
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reloaded.Warnings) != 2 || reloaded.Warnings[0] != want[0] || reloaded.Warnings[1] != want[1] {
+				t.Errorf("Expected warnings %+v after reload, got %+v", want, reloaded.Warnings)
+			}
+		})
+	}
+}
+
+func TestUpgradedWarningsSavedAsText(t *testing.T) {
+	for file, contents := range map[string]string{
+		"config.toml": "[sounds]\nwarning = \"builtin:break\"\n",
+		"config.yaml": "sounds:\n  warning: builtin:break\n",
+	} {
+		path := filepath.Join(t.TempDir(), file)
+		writeFile(t, path, contents)
+		cfg, err := LoadWithOptions(&Options{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.Warnings) != len(Phases) {
+			t.Fatalf("%s: expected the warning sound upgraded to warnings, got %+v", file, cfg.Warnings)
+		}
+		if err := cfg.Save(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "60000000000") {
+			t.Errorf("%s: expected the warnings in text, got:\n%s", file, data)
+		}
+		reloaded, err := LoadWithOptions(&Options{Path: path})
+		if err != nil {
+			t.Fatalf("%s: expected the saved file to load: %v", file, err)
+		}
+		if len(reloaded.Warnings) != len(Phases) {
+			t.Errorf("%s: expected the warnings after reload, got %+v", file, reloaded.Warnings)
+		}
+
+		// Once set up, removing every warning is not undone by the upgrade.
+		reloaded.Warnings = reloaded.Warnings[:0]
+		if err := reloaded.Save(); err != nil {
+			t.Fatal(err)
+		}
+		if reloaded, err = loadFile(path, formatOf(path)); err != nil {
+			t.Fatal(err)
+		}
+		if len(reloaded.Warnings) != 0 {
+			t.Errorf("%s: expected no warnings after removing them, got %+v", file, reloaded.Warnings)
+		}
+	}
+}
+
+func TestTOMLKeepsLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	contents := `# My pomodoro setup
+
+# Short sprints.
+focus_duration = "20m" # was 25m
+animation = 'slideshow' # or "pulse"
+long_break_interval = 3
+
+# Deep work profile.
+[profiles.deep] # weekends
+focus_duration = "90m"
+
+[[webhooks]] # chat status
+url = "https://example.com/a"
+events = [
+  "focus_start", # only this one
+]
+
+[webhooks.headers]
+Authorization = "Bearer a"
+
+[[webhooks]]
+url = "https://example.com/b"
+
+# The end.
+`
+	writeFile(t, path, contents)
+	cfg, err := LoadWithOptions(&Options{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.FocusDuration = 30 * time.Minute
+	cfg.Webhooks[1].Headers = map[string]string{"Authorization": "Bearer b"}
+	cfg.Webhooks = append(cfg.Webhooks, Webhook{URL: "https://example.com/c"})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+
+	last := -1
+	for _, want := range []string{
+		"# My pomodoro setup\n\n# Short sprints.\nfocus_duration = \"30m0s\" # was 25m\n",
+		"animation = \"slideshow\" # or \"pulse\"\nlong_break_interval = 3\n",
+		"\n# Deep work profile.\n[profiles.deep] # weekends\n",
+		"[[webhooks]] # chat status\nurl = \"https://example.com/a\"\n",
+		"[webhooks.headers]\nAuthorization = \"Bearer a\"\n",
+		"[[webhooks]]\nurl = \"https://example.com/b\"\n",
+		"[webhooks.headers]\nAuthorization = \"Bearer b\"\n",
+		"[[webhooks]]\nurl = \"https://example.com/c\"\n",
+		"\n# The end.\n",
+	} {
+		i := strings.Index(saved, want)
+		if i <= last {
+			t.Fatalf("Expected %q after the previous part, got:\n%s", want, saved)
+		}
+		last = i
+	}
+
+	reloaded, err := loadFile(path, formatTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.FocusDuration != 30*time.Minute || reloaded.Profiles["deep"].FocusDuration != 90*time.Minute {
+		t.Errorf("Unexpected config after reload: %+v", reloaded)
+	}
+	if len(reloaded.Webhooks) != 3 || reloaded.Webhooks[0].Headers["Authorization"] != "Bearer a" ||
+		reloaded.Webhooks[1].Headers["Authorization"] != "Bearer b" || reloaded.Webhooks[2].URL != "https://example.com/c" {
+		t.Errorf("Unexpected webhooks after reload: %+v", reloaded.Webhooks)
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+// Events that show a notification but have no sound of their own.
+const (
+	EventReset     = "reset"
+	EventInactive  = "inactive" // Start was pressed during an inactive period
+	EventBell      = "bell"
+	EventCountdown = "countdown"
+)
+
+// MessageEvents lists every event with a notification, in the order they are
+// shown in Settings.
+var MessageEvents = []string{
+	EventFocusStart,
+	EventBreakStart,
+	EventLongBreakStart,
+	EventSessionComplete,
+	EventPause,
+	EventReset,
+	EventInactive,
+	EventWarning,
+	EventBell,
+	EventCountdown,
+}
+
+// Placeholders are replaced in message templates: the current phase, the
+// one after it, the pomodoro within the cycle ("2/4"), the current task and
+// the time left.
+var Placeholders = []string{"{phase}", "{next}", "{cycle}", "{task}", "{remaining}"}
+
+// Message is the text of a notification. Each field is a template: text
+// with placeholders, or the key of a translated text. Empty fields use the
+// translated default of the event.
+type Message struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Message returns the templates configured for event.
+func (c *Config) Message(event string) Message {
+	return c.Messages[event]
+}
+
+// SetMessage sets the templates of event; an empty Message restores the
+// defaults.
+func (c *Config) SetMessage(event string, m Message) {
+	if m == (Message{}) {
+		delete(c.Messages, event)
+		return
+	}
+	if c.Messages == nil {
+		c.Messages = make(map[string]Message)
+	}
+	c.Messages[event] = m
+}
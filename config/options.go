@@ -0,0 +1,267 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of the environment variables that override settings,
+// e.g. POMODORO_FOCUS=50m.
+const EnvPrefix = "POMODORO_"
+
+// Options selects where the config is read from and what is layered over it.
+// Precedence, lowest first: defaults, config file, profile, environment,
+// command-line flags.
+type Options struct {
+	Path     string // config file; empty means the default location
+	Profile  string // name of an entry in Config.Profiles
+	Portable bool   // keep config and data next to the executable
+
+	Export    string // write a settings bundle to this file and exit
+	Import    string // import a settings bundle from this file and exit
+	AssumeYes bool   // apply an import without asking for confirmation
+
+	env   map[string]string // setting flag name -> value from the environment
+	flags map[string]string // setting flag name -> value from the command line
+}
+
+// setting is a value that can be overridden from the environment or the
+// command line. The environment variable is EnvPrefix plus the flag name in
+// upper case with dashes replaced by underscores.
+type setting struct {
+	flag   string
+	key    string // JSON key in the config file
+	usage  string
+	isBool bool
+	apply  func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{flag: "focus", key: "focus_duration", usage: "focus duration, e.g. 50m", apply: func(c *Config, v string) error {
+		return parseDuration(v, &c.FocusDuration)
+	}},
+	{flag: "short-break", key: "short_break_duration", usage: "short break duration, e.g. 5m", apply: func(c *Config, v string) error {
+		return parseDuration(v, &c.ShortBreakDuration)
+	}},
+	{flag: "long-break", key: "long_break_duration", usage: "long break duration, e.g. 15m", apply: func(c *Config, v string) error {
+		return parseDuration(v, &c.LongBreakDuration)
+	}},
+	{flag: "long-break-interval", key: "long_break_interval", usage: "pomodoros before a long break", apply: func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid interval %q", v)
+		}
+		c.LongBreakInterval = n
+		return nil
+	}},
+	{flag: "start-on-launch", key: "start_on_launch", usage: "start the timer when the app opens", isBool: true, apply: func(c *Config, v string) error {
+		return parseBool(v, &c.StartOnLaunch)
+	}},
+	{flag: "auto-start-cycles", key: "auto_start_cycles", usage: "start the next phase automatically", isBool: true, apply: func(c *Config, v string) error {
+		return parseBool(v, &c.AutoStartCycles)
+	}},
+	{flag: "animation", key: "animation", usage: "animation mode: icons or slideshow", apply: func(c *Config, v string) error {
+		if v != "icons" && v != "slideshow" {
+			return fmt.Errorf("invalid animation %q", v)
+		}
+		c.Animation = v
+		return nil
+	}},
+	{flag: "audio-output", key: "audio_output", usage: "where sound goes: device, null or record", apply: func(c *Config, v string) error {
+		if v != "device" && v != "null" && v != "record" {
+			return fmt.Errorf("invalid audio output %q", v)
+		}
+		c.AudioOutput = v
+		return nil
+	}},
+	{flag: "notifier", key: "notifier", usage: "how notifications are shown: auto, dbus, notify-send, fyne, stdout or none", apply: func(c *Config, v string) error {
+		switch v {
+		case "auto", "dbus", "notify-send", "fyne", "stdout", "none":
+			c.Notifier = v
+			return nil
+		}
+		return fmt.Errorf("invalid notifier %q", v)
+	}},
+}
+
+// ParseOptions reads the command line (without the program name) and the
+// environment (as returned by os.Environ). It returns flag.ErrHelp when help
+// was requested.
+func ParseOptions(args []string, environ []string, output io.Writer) (*Options, error) {
+	opts := &Options{env: map[string]string{}, flags: map[string]string{}}
+
+	env := map[string]string{}
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, EnvPrefix) {
+			env[strings.TrimPrefix(name, EnvPrefix)] = value
+		}
+	}
+	opts.Path = env["CONFIG"]
+	opts.Profile = env["PROFILE"]
+	opts.Portable, _ = strconv.ParseBool(env["PORTABLE"])
+	for _, s := range settings {
+		if value, ok := env[envName(s.flag)]; ok {
+			opts.env[s.flag] = value
+		}
+	}
+
+	fs := flag.NewFlagSet(AppName, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&opts.Path, "config", opts.Path, "path to the config file (env "+EnvPrefix+"CONFIG)")
+	fs.StringVar(&opts.Profile, "profile", opts.Profile, "profile to apply from the config file (env "+EnvPrefix+"PROFILE)")
+	fs.BoolVar(&opts.Portable, "portable", opts.Portable, "keep config and data next to the executable (env "+EnvPrefix+"PORTABLE, or a '"+PortableMarker+"' file beside it)")
+	fs.StringVar(&opts.Export, "export", "", "export all settings to a bundle file and exit")
+	fs.StringVar(&opts.Import, "import", "", "preview and import settings from a bundle file and exit")
+	fs.BoolVar(&opts.AssumeYes, "yes", false, "apply --import without asking for confirmation")
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s (env %s%s)", s.usage, EnvPrefix, envName(s.flag))
+		set := func(value string) error {
+			if err := s.apply(defaults(), value); err != nil {
+				return err
+			}
+			opts.flags[s.flag] = value
+			return nil
+		}
+		if s.isBool {
+			fs.Var(boolFlag{set}, s.flag, usage)
+		} else {
+			fs.Func(s.flag, usage, set)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// LoadWithOptions loads the config file selected by opts and layers the
+// profile, environment and flag overrides on top. Overridden values are used
+// for this run only and are never written back by Save.
+func LoadWithOptions(opts *Options) (*Config, error) {
+	paths, err := resolvePaths(opts.Portable || portableMarkerExists())
+	if err != nil {
+		return nil, err
+	}
+
+	path := opts.Path
+	if path == "" {
+		migrate(paths)
+		path = findConfigFile(paths.ConfigDir)
+	}
+
+	cfg, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg.paths = paths
+	base := *cfg
+	cfg.base = &base
+	cfg.overridden = map[string]bool{}
+
+	if opts.Profile != "" {
+		profile, ok := cfg.Profiles[opts.Profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q", opts.Profile)
+		}
+		cfg.applyProfile(profile)
+	}
+
+	for _, layer := range []map[string]string{opts.env, opts.flags} {
+		for _, s := range settings {
+			value, ok := layer[s.flag]
+			if !ok {
+				continue
+			}
+			if err := s.apply(cfg, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.flag, err)
+			}
+			cfg.overridden[s.key] = true
+		}
+	}
+	if err := cfg.recordLaunch(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// recordLaunch remembers the overridden values, so that encode can tell them
+// from later changes.
+func (c *Config) recordLaunch() error {
+	c.launch = nil
+	if len(c.overridden) == 0 {
+		return nil
+	}
+	values, err := toMap(c)
+	if err != nil {
+		return err
+	}
+	c.launch = make(map[string]json.RawMessage, len(c.overridden))
+	for key := range c.overridden {
+		c.launch[key] = values[key]
+	}
+	return nil
+}
+
+func (c *Config) applyProfile(p Profile) {
+	if p.FocusDuration > 0 {
+		c.FocusDuration = p.FocusDuration
+		c.overridden["focus_duration"] = true
+	}
+	if p.ShortBreakDuration > 0 {
+		c.ShortBreakDuration = p.ShortBreakDuration
+		c.overridden["short_break_duration"] = true
+	}
+	if p.LongBreakDuration > 0 {
+		c.LongBreakDuration = p.LongBreakDuration
+		c.overridden["long_break_duration"] = true
+	}
+	if p.LongBreakInterval > 0 {
+		c.LongBreakInterval = p.LongBreakInterval
+		c.overridden["long_break_interval"] = true
+	}
+}
+
+func envName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func parseDuration(v string, d *time.Duration) error {
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		// A bare number is taken as minutes, like in the Settings tab.
+		mins, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		parsed = time.Duration(mins) * time.Minute
+	}
+	if parsed <= 0 {
+		return fmt.Errorf("invalid duration %q", v)
+	}
+	*d = parsed
+	return nil
+}
+
+func parseBool(v string, b *bool) error {
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q", v)
+	}
+	*b = parsed
+	return nil
+}
+
+// boolFlag lets a Func flag be given without a value, like flag.Bool.
+type boolFlag struct {
+	set func(string) error
+}
+
+func (b boolFlag) String() string     { return "" }
+func (b boolFlag) Set(v string) error { return b.set(v) }
+func (b boolFlag) IsBoolFlag() bool   { return true }
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadWithOptionsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	file := `{"focus_duration": 1500000000000, "short_break_duration": 300000000000,
+		"long_break_duration": 900000000000, "long_break_interval": 4,
+		"profiles": {"deep": {"focus_duration": 3000000000000, "short_break_duration": 600000000000}}}`
+	if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	environ := []string{
+		"POMODORO_CONFIG=" + path,
+		"POMODORO_PROFILE=deep",
+		"POMODORO_SHORT_BREAK=7m",
+		"POMODORO_LONG_BREAK=20m",
+		"POMODORO_AUDIO_OUTPUT=record",
+	}
+	opts, err := ParseOptions([]string{"--long-break", "30m", "--auto-start-cycles=false", "--notifier", "stdout"}, environ, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{"profile over file", cfg.FocusDuration, 50 * time.Minute},
+		{"env over profile", cfg.ShortBreakDuration, 7 * time.Minute},
+		{"flag over env", cfg.LongBreakDuration, 30 * time.Minute},
+		{"file value kept", cfg.LongBreakInterval, 4},
+		{"bool flag", cfg.AutoStartCycles, false},
+		{"env audio output", cfg.AudioOutput, "record"},
+		{"flag notifier", cfg.Notifier, "stdout"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestSaveKeepsOverridesOutOfFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	opts, err := ParseOptions([]string{"--config", path, "--focus", "50m"}, nil, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Animation = "slideshow"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.FocusDuration != 25*time.Minute {
+		t.Errorf("Expected overridden focus duration not to be saved, got %v", saved.FocusDuration)
+	}
+	if saved.Animation != "slideshow" {
+		t.Errorf("Expected regular change to be saved, got %q", saved.Animation)
+	}
+}
+
+func TestParseOptionsRejectsInvalidValues(t *testing.T) {
+	if _, err := ParseOptions([]string{"--focus", "soon"}, nil, io.Discard); err == nil {
+		t.Error("Expected an error for an invalid duration")
+	}
+}
+
+func TestSaveKeepsEditsOfOverriddenValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	opts, err := ParseOptions([]string{"--config", path, "--focus", "50m", "--short-break", "10m"}, nil, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changed in Settings after launch.
+	cfg.FocusDuration = 40 * time.Minute
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadWithOptions(&Options{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.FocusDuration != 40*time.Minute {
+		t.Errorf("Expected the edit of an overridden value to be kept, got %v", reloaded.FocusDuration)
+	}
+	if reloaded.ShortBreakDuration != 5*time.Minute {
+		t.Errorf("Expected the untouched override not to be saved, got %v", reloaded.ShortBreakDuration)
+	}
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// DirName is the directory used for settings and data under the user's
+	// config and data homes.
+	DirName = "pomodoro-do-ben"
+
+	// PortableMarker is a file that, when placed next to the executable,
+	// switches the app to portable mode.
+	PortableMarker = "portable"
+
+	configFileName = "config.json"
+)
+
+// Paths are the directories the app reads and writes. Settings live in
+// ConfigDir; history, media and other data in DataDir.
+type Paths struct {
+	ConfigDir string
+	DataDir   string
+	Portable  bool
+}
+
+// resolvePaths picks the settings and data directories. In portable mode both
+// are the executable's directory. Otherwise settings follow XDG_CONFIG_HOME
+// (via os.UserConfigDir) and data follows XDG_DATA_HOME.
+func resolvePaths(portable bool) (Paths, error) {
+	if portable {
+		dir, err := executableDir()
+		if err != nil {
+			return Paths{}, err
+		}
+		return Paths{ConfigDir: dir, DataDir: dir, Portable: true}, nil
+	}
+
+	configHome, err := os.UserConfigDir()
+	if err != nil {
+		return Paths{}, err
+	}
+	dataHome, err := userDataDir()
+	if err != nil {
+		return Paths{}, err
+	}
+	return Paths{
+		ConfigDir: filepath.Join(configHome, DirName),
+		DataDir:   filepath.Join(dataHome, DirName),
+	}, nil
+}
+
+// userDataDir is the data counterpart of os.UserConfigDir.
+func userDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+		return os.UserConfigDir()
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// executable is os.Executable, replaceable in tests.
+var executable = os.Executable
+
+func executableDir() (string, error) {
+	exe, err := executable()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return filepath.Dir(exe), nil
+}
+
+func portableMarkerExists() bool {
+	dir, err := executableDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, PortableMarker))
+	return err == nil
+}
+
+// hasConfig reports whether dir holds a config file in any format.
+func hasConfig(dir string) bool {
+	for _, name := range configFileNames {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// migrate brings existing settings and data into paths the first time that
+// location is used, i.e. when its config directory has no config yet. Files
+// from the legacy "Pomodoro do Ben" directory are moved: the config files to
+// ConfigDir and everything else (history, media) to DataDir. When switching to
+// portable mode the regular config and data are copied, so the installed app
+// keeps its settings.
+func migrate(paths Paths) {
+	if hasConfig(paths.ConfigDir) {
+		return
+	}
+
+	type source struct {
+		configDir string
+		dataDir   string
+		move      bool
+	}
+	var sources []source
+	if paths.Portable {
+		if regular, err := resolvePaths(false); err == nil {
+			sources = append(sources, source{configDir: regular.ConfigDir, dataDir: regular.DataDir})
+		}
+	}
+	if configHome, err := os.UserConfigDir(); err == nil {
+		legacy := filepath.Join(configHome, AppName)
+		sources = append(sources, source{configDir: legacy, dataDir: legacy, move: !paths.Portable})
+	}
+
+	for _, src := range sources {
+		if src.configDir == paths.ConfigDir || !hasConfig(src.configDir) {
+			continue
+		}
+		if err := migrateFrom(src.configDir, src.dataDir, paths, src.move); err != nil {
+			fmt.Println("Error migrating settings:", err)
+			return
+		}
+		if src.move {
+			// Only removes the old directory if nothing else was left in it.
+			os.Remove(src.configDir)
+		}
+		fmt.Println("Migrated settings from", src.configDir, "to", paths.ConfigDir)
+		return
+	}
+}
+
+// migrateFrom transfers the config files of configDir and the entries of
+// dataDir into paths. Data that already exists at the destination is kept.
+func migrateFrom(configDir, dataDir string, paths Paths, move bool) error {
+	if err := os.MkdirAll(paths.ConfigDir, 0755); err != nil {
+		return err
+	}
+	configFiles := map[string]bool{}
+	for _, name := range configFileNames {
+		for _, name := range []string{name, backupPath(name)} {
+			configFiles[name] = true
+			from := filepath.Join(configDir, name)
+			if !fileExists(from) {
+				continue
+			}
+			if err := transferFile(from, filepath.Join(paths.ConfigDir, name), move); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dataDir == paths.DataDir {
+		return nil
+	}
+	entries, err := os.ReadDir(dataDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(paths.DataDir, 0755); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if configFiles[e.Name()] {
+			continue // where the data dir is the config dir too
+		}
+		to := filepath.Join(paths.DataDir, e.Name())
+		if _, err := os.Lstat(to); err == nil {
+			continue
+		}
+		if err := transferTree(filepath.Join(dataDir, e.Name()), to, move); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferTree moves or copies the file or directory from to to.
+func transferTree(from, to string, move bool) error {
+	if move {
+		if err := os.Rename(from, to); err == nil {
+			return nil
+		}
+	}
+	err := filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return transferFile(path, target, false)
+	})
+	if err != nil || !move {
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func transferFile(from, to string, move bool) error {
+	if move {
+		if err := os.Rename(from, to); err == nil {
+			return nil
+		}
+		// Rename fails across filesystems; fall back to copy and remove.
+	}
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(to, data); err != nil {
+		return err
+	}
+	if move {
+		return os.Remove(from)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMigratesLegacyConfigDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	legacyDir := filepath.Join(home, AppName)
+	if err := os.MkdirAll(legacyDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacyDir, "config.json"), []byte(`{"focus_duration": 3000000000000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FocusDuration != 50*time.Minute {
+		t.Errorf("Expected migrated focus duration, got %v", cfg.FocusDuration)
+	}
+	if _, err := os.Stat(filepath.Join(home, DirName, "config.json")); err != nil {
+		t.Errorf("Expected config in new location: %v", err)
+	}
+	if _, err := os.Stat(legacyDir); !os.IsNotExist(err) {
+		t.Errorf("Expected legacy directory to be removed, got %v", err)
+	}
+	if got := cfg.Paths().DataDir; got != filepath.Join(home, "data", DirName) {
+		t.Errorf("Expected data dir under XDG_DATA_HOME, got %s", got)
+	}
+}
+
+func TestLoadMigratesLegacyTOMLAndData(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	legacyDir := filepath.Join(home, "config", AppName)
+	writeFile(t, filepath.Join(legacyDir, "config.toml"), `focus_duration = "50m"`)
+	writeFile(t, filepath.Join(legacyDir, "media", "focar", "f1.mp3"), "mp3")
+
+	cfg, err := LoadWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.FocusDuration != 50*time.Minute {
+		t.Errorf("Expected the TOML config to be migrated, got %v", cfg.FocusDuration)
+	}
+	if _, err := os.Stat(filepath.Join(home, "config", DirName, "config.toml")); err != nil {
+		t.Errorf("Expected config.toml in the new config dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "data", DirName, "media", "focar", "f1.mp3")); err != nil {
+		t.Errorf("Expected media in the new data dir: %v", err)
+	}
+	if _, err := os.Stat(legacyDir); !os.IsNotExist(err) {
+		t.Errorf("Expected legacy directory to be removed, got %v", err)
+	}
+}
+
+func TestXDGHomesResolvedSeparately(t *testing.T) {
+	configHome, dataHome := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	cfg, err := LoadWithOptions(&Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := cfg.Paths()
+	if paths.ConfigDir != filepath.Join(configHome, DirName) || paths.DataDir != filepath.Join(dataHome, DirName) || paths.Portable {
+		t.Errorf("Expected config under %s and data under %s, got %+v", configHome, dataHome, paths)
+	}
+}
+
+func TestPortableMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	regular := filepath.Join(home, "config", DirName, "config.json")
+	writeFile(t, regular, `{"focus_duration": 3000000000000}`)
+	writeFile(t, filepath.Join(home, "data", DirName, "history.json"), "[]")
+
+	tests := []struct {
+		name   string
+		marker bool
+		opts   Options
+	}{
+		{"marker", true, Options{}},
+		{"flag", false, Options{Portable: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exeDir := t.TempDir()
+			old := executable
+			executable = func() (string, error) { return filepath.Join(exeDir, "pomodoro"), nil }
+			t.Cleanup(func() { executable = old })
+			if tt.marker {
+				writeFile(t, filepath.Join(exeDir, PortableMarker), "")
+			}
+
+			cfg, err := LoadWithOptions(&tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths := cfg.Paths()
+			if !paths.Portable || paths.ConfigDir != exeDir || paths.DataDir != exeDir {
+				t.Errorf("Expected portable paths in %s, got %+v", exeDir, paths)
+			}
+			if cfg.FocusDuration != 50*time.Minute {
+				t.Errorf("Expected the regular config to be copied, got %v", cfg.FocusDuration)
+			}
+			if _, err := os.Stat(filepath.Join(exeDir, "history.json")); err != nil {
+				t.Errorf("Expected the regular data to be copied: %v", err)
+			}
+			if _, err := os.Stat(regular); err != nil {
+				t.Errorf("Expected the regular config to be left in place: %v", err)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSaveDelay is how long a Saver waits for further changes before
+// writing the config to disk.
+const DefaultSaveDelay = 500 * time.Millisecond
+
+// Saver coalesces rapid changes (e.g. one per keystroke in a settings entry)
+// into a single write. The config is snapshotted when Request is called, so
+// the write never races with later edits.
+type Saver struct {
+	cfg   *Config
+	delay time.Duration
+
+	writeMu sync.Mutex // serializes writes so an older snapshot never lands last
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	pending []byte
+	onError func(error)
+}
+
+func NewSaver(cfg *Config, delay time.Duration) *Saver {
+	return &Saver{cfg: cfg, delay: delay}
+}
+
+// OnError registers a callback for failed writes. It is called with nil after
+// a successful write, so a previously reported error can be cleared.
+func (s *Saver) OnError(f func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = f
+}
+
+// Request schedules a save of the current config, restarting the delay if a
+// save is already pending.
+func (s *Saver) Request() {
+	data, err := s.cfg.encode()
+
+	s.mu.Lock()
+	if err != nil {
+		onError := s.onError
+		s.mu.Unlock()
+		if onError != nil {
+			onError(err)
+		}
+		return
+	}
+	s.pending = data
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(s.delay, func() { s.Flush() })
+	s.mu.Unlock()
+}
+
+// Flush writes any pending change immediately.
+func (s *Saver) Flush() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	data := s.pending
+	s.pending = nil
+	onError := s.onError
+	s.mu.Unlock()
+
+	if data == nil {
+		return nil
+	}
+
+	path, err := s.cfg.filePath()
+	if err == nil {
+		err = writeConfig(path, data)
+	}
+	if onError != nil {
+		onError(err)
+	}
+	return err
+}
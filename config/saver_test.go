@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaverCoalescesRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := defaults()
+	cfg.path = path
+	s := NewSaver(cfg, time.Hour)
+	writes := 0
+	s.OnError(func(err error) {
+		if err != nil {
+			t.Errorf("Unexpected write error: %v", err)
+		}
+		writes++
+	})
+
+	for i := 1; i <= 10; i++ {
+		cfg.LongBreakInterval = i
+		s.Request()
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected nothing written before the delay, got %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if writes != 1 {
+		t.Errorf("Expected 1 write, got %d", writes)
+	}
+	// A second write would have kept the first as a backup.
+	if _, err := os.Stat(backupPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected no backup after a single write, got %v", err)
+	}
+	saved, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.LongBreakInterval != 10 {
+		t.Errorf("Expected the last change to be written, got %d", saved.LongBreakInterval)
+	}
+}
+
+func TestSaverReportsWriteErrors(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := defaults()
+	cfg.path = filepath.Join(blocker, "config.json") // its directory is a file
+	s := NewSaver(cfg, time.Hour)
+	var reported error
+	s.OnError(func(err error) { reported = err })
+
+	s.Request()
+	err := s.Flush()
+	if err == nil || reported != err {
+		t.Errorf("Expected the write error %v to reach OnError, got %v", err, reported)
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "strings"
+
+// Events that can have a sound, used as keys of Config.Sounds.
+const (
+	EventFocusStart      = "focus_start"
+	EventBreakStart      = "break_start"
+	EventLongBreakStart  = "long_break_start"
+	EventPause           = "pause"
+	EventWarning         = "warning"          // shortly before a phase ends
+	EventSessionComplete = "session_complete" // a long break ends, completing a full cycle
+)
+
+// Events lists every event in the order they are shown in Settings.
+var Events = []string{
+	EventFocusStart,
+	EventBreakStart,
+	EventLongBreakStart,
+	EventPause,
+	EventWarning,
+	EventSessionComplete,
+}
+
+// Sound values that are not file paths.
+const (
+	SoundNone          = ""
+	BuiltinSoundPrefix = "builtin:"
+)
+
+// Sound returns the sound configured for event.
+func (c *Config) Sound(event string) string {
+	return c.Sounds[event]
+}
+
+// SetSound sets the sound played for event.
+func (c *Config) SetSound(event, sound string) {
+	if c.Sounds == nil {
+		c.Sounds = make(map[string]string)
+	}
+	c.Sounds[event] = sound
+}
+
+// BuiltinSound reports whether sound names a built-in sound and returns its
+// name.
+func BuiltinSound(sound string) (string, bool) {
+	return strings.CutPrefix(sound, BuiltinSoundPrefix)
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSoundsMergeWithDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"sounds": {"pause": "/tmp/click.wav", "break_start": ""}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := readConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Sound(EventPause); got != "/tmp/click.wav" {
+		t.Errorf("Expected the configured pause sound, got %q", got)
+	}
+	if got := cfg.Sound(EventBreakStart); got != SoundNone {
+		t.Errorf("Expected the break sound to be turned off, got %q", got)
+	}
+	if name, ok := BuiltinSound(cfg.Sound(EventFocusStart)); !ok || name != "focus" {
+		t.Errorf("Expected the default built-in focus sound, got %q", cfg.Sound(EventFocusStart))
+	}
+
+	// Reading a bundle must not touch the current sounds.
+	bundlePath := filepath.Join(dir, "bundle.json")
+	if err := os.WriteFile(bundlePath, []byte(`{"sounds": {"pause": "/tmp/other.wav"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := cfg.ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Sound(EventPause) != "/tmp/click.wav" || imported.Sound(EventPause) != "/tmp/other.wav" {
+		t.Errorf("Expected the bundle to change only the copy, got %q and %q", cfg.Sound(EventPause), imported.Sound(EventPause))
+	}
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlSection is one table of a TOML file, kept as lines: the comment and
+// blank lines above its header, the header (empty for the root table) and its
+// key/value pairs.
+type tomlSection struct {
+	id      string // e.g. "", "profiles.deep", "webhooks[1]", "webhooks[1].headers"
+	leading []string
+	header  string
+	comment string // at the end of the header line, with its "#"
+	entries []tomlEntry
+}
+
+type tomlEntry struct {
+	key     string // as in tomlKey
+	leading []string
+	lines   []string // more than one for multi-line arrays and strings
+	comment string   // at the end of a one-line pair, with its "#"
+}
+
+// tomlPair is a table of the updated document and the same table in the old
+// one, if it was there.
+type tomlPair struct{ old, updated *tomlSection }
+
+// keepTOMLLayout carries the table and key order, comments and blank lines of
+// old over to updated, a document written by the TOML encoder, so that a
+// hand-edited TOML file keeps its layout. Tables and keys only in updated go
+// after the ones before them in updated. updated is returned unchanged if the
+// result would not hold the same values.
+func keepTOMLLayout(old, updated []byte) []byte {
+	oldSections, trailing := parseTOMLLayout(old)
+	newSections, _ := parseTOMLLayout(updated)
+
+	byID := map[string]*tomlSection{}
+	for _, s := range newSections {
+		byID[s.id] = s
+	}
+	var order []tomlPair
+	placed := map[string]int{} // index in order by id
+	for _, s := range oldSections {
+		if n, ok := byID[s.id]; ok {
+			placed[s.id] = len(order)
+			order = append(order, tomlPair{s, n})
+		}
+	}
+	for i, s := range newSections {
+		if _, ok := placed[s.id]; ok {
+			continue
+		}
+		// Right after the table before it in updated, and after the
+		// sub-tables of the array element that one belongs to.
+		at := placed[newSections[i-1].id] + 1
+		if group := arrayElement(newSections[i-1].id); group != "" {
+			for at < len(order) && strings.HasPrefix(order[at].updated.id, group+".") {
+				at++
+			}
+		}
+		order = append(order[:at], append([]tomlPair{{nil, s}}, order[at:]...)...)
+		for j := at; j < len(order); j++ {
+			placed[order[j].updated.id] = j
+		}
+	}
+
+	var lines []string
+	for _, p := range order {
+		if p.old == nil {
+			if len(p.updated.entries) == 0 && implied(p.updated.id, order) {
+				continue
+			}
+			lines = append(lines, "", p.updated.header)
+			for _, e := range p.updated.entries {
+				lines = append(lines, e.lines...)
+			}
+			continue
+		}
+		lines = append(lines, p.old.leading...)
+		if p.updated.header != "" {
+			lines = append(lines, withComment(p.updated.header, p.old.comment))
+		}
+		entries := map[string]tomlEntry{}
+		for _, e := range p.updated.entries {
+			entries[e.key] = e
+		}
+		for _, oldEntry := range p.old.entries {
+			e, ok := entries[oldEntry.key]
+			if !ok {
+				continue
+			}
+			delete(entries, oldEntry.key)
+			lines = append(lines, oldEntry.leading...)
+			if len(e.lines) == 1 {
+				lines = append(lines, withComment(e.lines[0], oldEntry.comment))
+			} else {
+				lines = append(lines, e.lines...)
+			}
+		}
+		for _, e := range p.updated.entries {
+			if _, ok := entries[e.key]; ok {
+				lines = append(lines, e.lines...)
+			}
+		}
+	}
+	lines = append(lines, trailing...)
+	result := []byte(strings.Join(lines, "\n") + "\n")
+
+	var want, got map[string]any
+	if toml.Unmarshal(updated, &want) != nil || toml.Unmarshal(result, &got) != nil || !reflect.DeepEqual(want, got) {
+		return updated
+	}
+	return result
+}
+
+// implied reports whether a table with no keys of its own is defined by one
+// of its sub-tables anyway, which makes its header unnecessary.
+func implied(id string, order []tomlPair) bool {
+	for _, p := range order {
+		if strings.HasPrefix(p.updated.id, id+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func withComment(line, comment string) string {
+	if comment == "" {
+		return line
+	}
+	return line + " " + comment
+}
+
+// arrayElement returns the array-of-tables element that the table id belongs
+// to, e.g. "webhooks[1]" for "webhooks[1].headers", or "" for none.
+func arrayElement(id string) string {
+	if i := strings.LastIndex(id, "]"); i >= 0 {
+		return id[:i+1]
+	}
+	return ""
+}
+
+// parseTOMLLayout splits a TOML document into its tables. Comment and blank
+// lines after the last key are returned as trailing.
+func parseTOMLLayout(data []byte) (sections []*tomlSection, trailing []string) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	current := &tomlSection{}
+	sections = []*tomlSection{current}
+	elements := map[string]string{} // id of the latest element by array name
+	counts := map[string]int{}      // elements by array id
+	var pending []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, line)
+
+		case strings.HasPrefix(trimmed, "["):
+			current = &tomlSection{leading: pending, header: line}
+			var scan tomlScanner
+			if at := scan.line(line); at >= 0 {
+				current.header, current.comment = strings.TrimRight(line[:at], " \t"), line[at:]
+			}
+			header := strings.TrimSpace(current.header)
+			name := tomlKey(strings.Trim(header, "[]"))
+			// Sub-tables of an array element belong to its latest element.
+			current.id = name
+			parent := ""
+			for array := range elements {
+				if strings.HasPrefix(name, array+".") && len(array) > len(parent) {
+					parent = array
+				}
+			}
+			if parent != "" {
+				current.id = elements[parent] + name[len(parent):]
+			}
+			if strings.HasPrefix(header, "[[") {
+				base := current.id
+				current.id = fmt.Sprintf("%s[%d]", base, counts[base])
+				counts[base]++
+				elements[name] = current.id
+			}
+			sections = append(sections, current)
+			pending = nil
+
+		default:
+			e := tomlEntry{key: tomlKey(trimmed), leading: pending, lines: []string{line}}
+			pending = nil
+			var scan tomlScanner
+			if at := scan.line(line); at >= 0 && scan.done() {
+				e.lines[0] = strings.TrimRight(line[:at], " \t")
+				e.comment = line[at:]
+			}
+			for !scan.done() && i+1 < len(lines) {
+				i++
+				e.lines = append(e.lines, lines[i])
+				scan.line(lines[i])
+			}
+			current.entries = append(current.entries, e)
+		}
+	}
+	return sections, pending
+}
+
+// tomlKey returns the key at the start of s, up to "=" or the end, with its
+// dotted parts trimmed and unquoted.
+func tomlKey(s string) string {
+	var (
+		parts []string
+		part  strings.Builder
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				part.WriteByte(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			parts = append(parts, strings.TrimSpace(part.String()))
+			part.Reset()
+		case c == '=':
+			return strings.Join(append(parts, strings.TrimSpace(part.String())), ".")
+		default:
+			part.WriteByte(c)
+		}
+	}
+	return strings.Join(append(parts, strings.TrimSpace(part.String())), ".")
+}
+
+// tomlScanner follows strings and brackets across the lines of a value.
+type tomlScanner struct {
+	quote string // delimiter of the open string: `"`, `'`, `"""` or `'''`
+	depth int    // open brackets and braces
+}
+
+// line scans the next line and returns where a comment starts in it, or -1.
+func (s *tomlScanner) line(line string) int {
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if s.quote != "" {
+			if c == '\\' && s.quote[0] == '"' {
+				i++
+			} else if strings.HasPrefix(line[i:], s.quote) {
+				i += len(s.quote) - 1
+				s.quote = ""
+			}
+			continue
+		}
+		switch c {
+		case '#':
+			return i
+		case '"', '\'':
+			s.quote = string(c)
+			if strings.HasPrefix(line[i:], strings.Repeat(s.quote, 3)) {
+				s.quote = strings.Repeat(s.quote, 3)
+			}
+			i += len(s.quote) - 1
+		case '[', '{':
+			s.depth++
+		case ']', '}':
+			s.depth--
+		}
+	}
+	if len(s.quote) == 1 { // one-line strings end with their line
+		s.quote = ""
+	}
+	return -1
+}
+
+func (s *tomlScanner) done() bool {
+	return s.quote == "" && s.depth <= 0
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Warning gives a heads-up Before the end of a phase: the sound of
+// EventWarning, a notification or both.
+type Warning struct {
+	Phase  string        `json:"phase"`
+	Before time.Duration `json:"before"`
+	Sound  bool          `json:"sound"`
+	Notify bool          `json:"notify"`
+}
+
+// UnmarshalJSON decodes a warning from scratch. Without it, the warnings a
+// config is decoded on top of, such as the saved ones under an imported
+// bundle, would fill the fields a warning in the file leaves out.
+func (w *Warning) UnmarshalJSON(data []byte) error {
+	type plain Warning
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*w = Warning(p)
+	return nil
+}
+
+// upgradeWarnings turns the warning sound of a config written before
+// warnings could be set up, which played a minute before every phase ended,
+// into the same warnings. Configs without a warning sound get none. data is
+// the config as JSON.
+func upgradeWarnings(data []byte, c *Config) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	if _, ok := keys["warnings"]; ok || c.Sounds[EventWarning] == SoundNone {
+		if c.Warnings == nil {
+			c.Warnings = []Warning{} // kept in the file, which TOML can't do with null
+		}
+		return nil
+	}
+	for _, phase := range Phases {
+		c.Warnings = append(c.Warnings, Warning{Phase: phase, Before: time.Minute, Sound: true})
+	}
+	return nil
+}
+
+// Due reports whether the warning is given when remaining is left of a phase
+// of the given length. The timer counts whole seconds, so it is due during
+// the second that ends at its time. Phases no longer than Before get none.
+func (w Warning) Due(length, remaining time.Duration) bool {
+	if w.Before <= 0 || w.Before >= length || remaining <= 0 {
+		return false
+	}
+	return remaining <= w.Before && remaining > w.Before-time.Second
+}
+
+// Threshold returns Before as SetThreshold reads it, e.g. "2m" or "30s".
+func (w Warning) Threshold() string {
+	return formatShortDuration(w.Before)
+}
+
+// SetThreshold sets Before from text such as "2m" or "30s".
+func (w *Warning) SetThreshold(s string) error {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d < time.Second {
+		return fmt.Errorf("invalid warning time %q", s)
+	}
+	w.Before = d
+	return nil
+}
+
+// WarningsIn returns the warnings of phase.
+func (c *Config) WarningsIn(phase string) []Warning {
+	var warnings []Warning
+	for _, w := range c.Warnings {
+		if w.Phase == phase {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarningDue(t *testing.T) {
+	const length = 5 * time.Minute
+	w := Warning{Phase: PhaseShortBreak}
+	if err := w.SetThreshold("2m"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Threshold() != "2m" {
+		t.Errorf("Expected 2m to read back, got %q", w.Threshold())
+	}
+	var due []time.Duration
+	for remaining := length; remaining >= 0; remaining -= time.Second {
+		if w.Due(length, remaining) {
+			due = append(due, remaining)
+		}
+	}
+	if len(due) != 1 || due[0] != 2*time.Minute {
+		t.Errorf("Expected a warning with 2m left, got %v", due)
+	}
+	// Extended to less than the threshold, the phase gets no warning.
+	if w.Due(time.Minute, time.Minute) || w.Due(2*time.Minute, 2*time.Minute) {
+		t.Error("Expected no warning in a phase no longer than the threshold")
+	}
+}
+
+func TestWarningSetThresholdRejects(t *testing.T) {
+	for _, s := range []string{"", "soon", "0s", "-1m", "500ms"} {
+		var w Warning
+		if err := w.SetThreshold(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestUpgradeWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     int
+	}{
+		{"no file", "", 0},
+		{"no warning sound", `{"focus_duration": 1500000000000}`, 0},
+		{"warning sound", `{"sounds": {"warning": "/tmp/ding.wav"}}`, len(Phases)},
+		{"warnings set up", `{"sounds": {"warning": "/tmp/ding.wav"}, "warnings": null}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if tt.contents != "" {
+				writeFile(t, path, tt.contents)
+			}
+			cfg, err := loadFile(path, formatJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cfg.Warnings) != tt.want {
+				t.Fatalf("Expected %d warnings, got %+v", tt.want, cfg.Warnings)
+			}
+			for _, w := range cfg.Warnings {
+				if w.Before != time.Minute || !w.Sound || w.Notify {
+					t.Errorf("Expected a one-minute warning sound, got %+v", w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// WebhookEvents lists the timer events a webhook can be called on, in the
+// order they are shown in Settings.
+var WebhookEvents = []string{
+	EventFocusStart,
+	EventBreakStart,
+	EventLongBreakStart,
+	EventSessionComplete,
+	EventPause,
+	EventReset,
+	EventWarning,
+}
+
+// WebhookPlaceholders are replaced in webhook bodies: the event, the phase
+// and the one after it (as in Phases), the pomodoro within the cycle
+// ("2/4"), the current task, the seconds left, the time of the event in
+// RFC 3339 and the whole JSON payload.
+var WebhookPlaceholders = []string{"{event}", "{phase}", "{next}", "{cycle}", "{task}", "{remaining}", "{timestamp}", "{payload}"}
+
+// Webhook is a URL that is POSTed to on timer events.
+type Webhook struct {
+	URL     string            `json:"url"`
+	Events  []string          `json:"events,omitempty"` // none means every event
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is a template with WebhookPlaceholders; empty sends the JSON
+	// payload as it is.
+	Body string `json:"body,omitempty"`
+}
+
+// Fires reports whether the webhook is called on event.
+func (w Webhook) Fires(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL returns an error unless s is an http or https URL.
+func CheckURL(s string) error {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q", s)
+	}
+	return nil
+}
+
+// HeaderText returns the headers as SetHeaderText reads them, one
+// "Name: value" per line, sorted by name.
+func (w Webhook) HeaderText() string {
+	var lines []string
+	for name, value := range w.Headers {
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// SetHeaderText sets the headers from lines of "Name: value". Blank lines
+// are skipped.
+func (w *Webhook) SetHeaderText(s string) error {
+	headers := make(map[string]string)
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("invalid header %q", line)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	if len(headers) == 0 {
+		headers = nil
+	}
+	w.Headers = headers
+	return nil
+}
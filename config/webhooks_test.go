@@ -0,0 +1,45 @@
+package config
+
+import "testing"
+
+func TestWebhookFires(t *testing.T) {
+	all := Webhook{URL: "http://localhost"}
+	if !all.Fires(EventPause) || !all.Fires(EventWarning) {
+		t.Error("Expected a webhook without events to fire on every event")
+	}
+	some := Webhook{URL: "http://localhost", Events: []string{EventFocusStart}}
+	if !some.Fires(EventFocusStart) || some.Fires(EventPause) {
+		t.Errorf("Expected only %v to fire, got %+v", some.Events, some)
+	}
+}
+
+func TestWebhookHeaderText(t *testing.T) {
+	var w Webhook
+	if err := w.SetHeaderText("X-Token: abc\n\nContent-Type: text/plain\n"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Content-Type: text/plain\nX-Token: abc"; w.HeaderText() != want {
+		t.Errorf("Expected %q, got %q", want, w.HeaderText())
+	}
+	for _, s := range []string{"no colon", ": empty name", "Two words: x"} {
+		if err := w.SetHeaderText(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+	if err := w.SetHeaderText(""); err != nil || w.Headers != nil {
+		t.Errorf("Expected empty text to clear the headers, got %v, %v", w.Headers, err)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, s := range []string{"http://localhost:8080/hook", "https://example.com"} {
+		if err := CheckURL(s); err != nil {
+			t.Errorf("Expected %q to be accepted: %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "example.com", "ftp://example.com", "http://"} {
+		if err := CheckURL(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
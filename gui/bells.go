@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/pomo"
+)
+
+// statePhase returns the config name of the phase of state.
+func statePhase(state pomo.State) string {
+	switch state {
+	case pomo.ShortBreakState:
+		return config.PhaseShortBreak
+	case pomo.LongBreakState:
+		return config.PhaseLongBreak
+	default:
+		return config.PhaseFocus
+	}
+}
+
+// ringBells plays the bells of the current phase that are due at tick e,
+// calling notifyBell for those with a notification.
+func ringBells(cfg *config.Config, e pomo.Event, notifyBell func()) {
+	for _, b := range cfg.BellsIn(statePhase(e.State)) {
+		if !b.Due(e.Duration, e.Duration-e.Remaining) {
+			continue
+		}
+		if file := soundFile(b.Sound); file != "" {
+			playSound(file)
+		}
+		if b.Notify {
+			notifyBell()
+		}
+	}
+}
+
+// newBellSettings returns the Settings rows editing the bells and a function
+// that refreshes them from cfg.
+func newBellSettings(cfg *config.Config, saver *config.Saver, w fyne.Window) (fyne.CanvasObject, func()) {
+	list := container.NewVBox()
+
+	var phaseLabels []string
+	for _, phase := range config.Phases {
+		phaseLabels = append(phaseLabels, i18n.T("phase_"+phase))
+	}
+
+	var rebuild func()
+	rebuild = func() {
+		list.RemoveAll()
+		for i := range cfg.Bells {
+			i := i
+			bell := &cfg.Bells[i]
+
+			phaseSelect := widget.NewSelect(phaseLabels, nil)
+			phaseSelect.Selected = i18n.T("phase_" + bell.Phase)
+			phaseSelect.OnChanged = func(s string) {
+				bell.Phase = config.Phases[slices.Index(phaseLabels, s)]
+				saver.Request()
+			}
+
+			whenEntry := widget.NewEntry()
+			whenEntry.SetPlaceHolder(i18n.T("bell_when"))
+			whenEntry.SetText(bell.When())
+			whenEntry.Validator = func(s string) error {
+				var b config.Bell
+				return b.SetWhen(s)
+			}
+			whenEntry.OnChanged = func(s string) {
+				if bell.SetWhen(s) == nil {
+					saver.Request()
+				}
+			}
+
+			everyEntry := widget.NewEntry()
+			everyEntry.SetPlaceHolder(i18n.T("bell_every"))
+			everyEntry.SetText(bell.Repeat())
+			everyEntry.Validator = func(s string) error {
+				var b config.Bell
+				return b.SetRepeat(s)
+			}
+			everyEntry.OnChanged = func(s string) {
+				if bell.SetRepeat(s) == nil {
+					saver.Request()
+				}
+			}
+
+			soundRow, _ := newSoundPicker(func() string { return bell.Sound }, func(sound string) {
+				bell.Sound = sound
+				saver.Request()
+				cacheEventSounds(cfg)
+			}, w)
+
+			notifyCheck := widget.NewCheck(i18n.T("bell_notify"), nil)
+			notifyCheck.Checked = bell.Notify
+			notifyCheck.OnChanged = func(checked bool) {
+				bell.Notify = checked
+				saver.Request()
+			}
+
+			removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				cfg.Bells = slices.Delete(cfg.Bells, i, i+1)
+				saver.Request()
+				cacheEventSounds(cfg)
+				rebuild()
+			})
+
+			list.Add(container.NewBorder(nil, nil, nil, removeButton, container.NewGridWithColumns(3, phaseSelect, whenEntry, everyEntry)))
+			list.Add(soundRow)
+			list.Add(notifyCheck)
+		}
+	}
+	rebuild()
+
+	addButton := widget.NewButtonWithIcon(i18n.T("bell_add"), theme.ContentAddIcon(), func() {
+		cfg.Bells = append(cfg.Bells, config.Bell{Phase: config.PhaseFocus, Fraction: 0.5, Sound: config.BuiltinSoundPrefix + "focus"})
+		saver.Request()
+		cacheEventSounds(cfg)
+		rebuild()
+	})
+
+	return container.NewVBox(list, addButton), rebuild
+}
@@ -0,0 +1,41 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/player"
+	"pomodoro-do-ben/pomo"
+)
+
+func TestRingBells(t *testing.T) {
+	rec := recorder()
+	file := filepath.Join(t.TempDir(), "bell.wav")
+	if err := os.WriteFile(file, silentWAV, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Bells: []config.Bell{
+		{Phase: config.PhaseShortBreak, Fraction: 0.5, Sound: file},
+		{Phase: config.PhaseFocus, At: time.Second, Sound: file},
+	}}
+
+	tests := []struct {
+		remaining time.Duration
+		rings     bool
+	}{
+		{6 * time.Second, false},
+		{5 * time.Second, true},
+		{4 * time.Second, false},
+	}
+	for _, tt := range tests {
+		before := len(rec.Events())
+		ringBells(cfg, pomo.Event{Kind: pomo.EventTick, State: pomo.ShortBreakState, Duration: 10 * time.Second, Remaining: tt.remaining}, func() {})
+		events := rec.Events()[before:]
+		if rang := len(events) == 1 && events[0].Kind == player.EventPlay && events[0].Sound == file; rang != tt.rings || len(events) > 1 {
+			t.Errorf("%v remaining: expected ring %v, got events %v", tt.remaining, tt.rings, events)
+		}
+	}
+}
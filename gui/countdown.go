@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/notifier"
+	"pomodoro-do-ben/pomo"
+)
+
+// countdownFinal is how long before the end of a phase the countdown
+// notification counts every second instead of every minute.
+const countdownFinal = 10 * time.Second
+
+// countdownDue reports whether the countdown is refreshed at a tick with
+// remaining time left.
+func countdownDue(remaining time.Duration) bool {
+	return remaining > 0 && (remaining%time.Minute == 0 || remaining <= countdownFinal)
+}
+
+// countdownNotification is the countdown notification, with the text of the
+// countdown message.
+func countdownNotification(title, body string) notifier.Notification {
+	return notifier.Notification{
+		Title:    title,
+		Body:     body,
+		Urgency:  notifier.UrgencyLow,
+		Category: "x-pomodoro.countdown",
+		Timeout:  notifier.Persistent,
+	}
+}
+
+// countdownSlot holds the latest countdown update until the goroutine that
+// talks to the desktop takes it. A newer update replaces one that was not
+// shown yet, so a stalled desktop never holds up the timer.
+type countdownSlot struct {
+	signal chan struct{} // buffered; set when there is an update to take
+
+	mu      sync.Mutex
+	pending bool
+	n       *notifier.Notification // nil closes the notification
+	waiters []chan struct{}        // closed once the update is made
+}
+
+func newCountdownSlot() *countdownSlot {
+	return &countdownSlot{signal: make(chan struct{}, 1)}
+}
+
+// put replaces the pending update with n, or with closing the notification
+// if n is nil. done, if not nil, is closed once an update made after it is
+// done.
+func (s *countdownSlot) put(n *notifier.Notification, done chan struct{}) {
+	s.mu.Lock()
+	s.pending, s.n = true, n
+	if done != nil {
+		s.waiters = append(s.waiters, done)
+	}
+	s.mu.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default: // already signalled
+	}
+}
+
+// take returns the pending update, if any.
+func (s *countdownSlot) take() (n *notifier.Notification, waiters []chan struct{}, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, waiters, ok = s.n, s.waiters, s.pending
+	s.pending, s.n, s.waiters = false, nil, nil
+	return n, waiters, ok
+}
+
+// startCountdown keeps a notification in step with the timer while
+// cfg.CountdownNotification is on, and closes it whenever the timer stops.
+// The desktop is talked to from a goroutine of its own, which only ever
+// shows the latest update, so the timer never waits for it. The returned
+// function closes the notification and waits until it is gone.
+func startCountdown(cfg *config.Config, timer *pomo.Timer) func() {
+	slot := newCountdownSlot()
+	go func() {
+		var countdown notifier.Countdown
+		for range slot.signal {
+			n, waiters, ok := slot.take()
+			if !ok {
+				continue
+			}
+			var err error
+			if n == nil {
+				err = countdown.Close()
+			} else {
+				err = countdown.Show(*n)
+			}
+			if err != nil {
+				fmt.Println("Error updating countdown notification:", err)
+			}
+			for _, done := range waiters {
+				close(done)
+			}
+		}
+	}()
+
+	timer.OnEvent(func(e pomo.Event) {
+		switch e.Kind {
+		case pomo.EventStarted, pomo.EventTick:
+			if !cfg.CountdownNotification || (e.Kind == pomo.EventTick && !countdownDue(e.Remaining)) {
+				return
+			}
+			vars := messageVars(cfg, timer)
+			vars["{remaining}"] = formatTime(e.Remaining)
+			n := countdownNotification(message(cfg, config.EventCountdown, vars))
+			slot.put(&n, nil)
+		default:
+			// Stopped, reset, or moved to a phase that has not started yet.
+			slot.put(nil, nil)
+		}
+	})
+	return func() {
+		done := make(chan struct{})
+		slot.put(nil, done)
+		<-done
+	}
+}
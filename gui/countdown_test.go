@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/notifier"
+	"pomodoro-do-ben/pomo"
+)
+
+func TestCountdownDue(t *testing.T) {
+	var due []time.Duration
+	for remaining := 3 * time.Minute; remaining >= 0; remaining -= time.Second {
+		if countdownDue(remaining) {
+			due = append(due, remaining)
+		}
+	}
+	// 3, 2 and 1 minutes, then the last 10 seconds.
+	if len(due) != 13 || due[0] != 3*time.Minute || due[2] != time.Minute || due[3] != 10*time.Second || due[12] != time.Second {
+		t.Errorf("Unexpected refreshes at %v", due)
+	}
+}
+
+func TestCountdownNotification(t *testing.T) {
+	n := countdownNotification("Short break", "1:30 left")
+	if n.Timeout != notifier.Persistent || n.Urgency != notifier.UrgencyLow {
+		t.Errorf("Expected a quiet persistent notification, got %+v", n)
+	}
+	if n.Title != "Short break" || n.Body != "1:30 left" {
+		t.Errorf("Expected the message text, got %q: %q", n.Title, n.Body)
+	}
+}
+
+// stalledNotifier blocks every update until released, like a desktop that
+// stopped answering.
+type stalledNotifier struct {
+	entered chan struct{} // gets a value when an update starts
+	release chan struct{}
+
+	mu      sync.Mutex
+	updates int
+	removed int
+}
+
+func (s *stalledNotifier) Name() string                       { return "stalled" }
+func (s *stalledNotifier) Send(n notifier.Notification) error { return nil }
+
+func (s *stalledNotifier) Update(id uint32, n notifier.Notification) (uint32, error) {
+	select {
+	case s.entered <- struct{}{}:
+	default:
+	}
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates++
+	return 1, nil
+}
+
+func (s *stalledNotifier) Remove(id uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removed++
+	return nil
+}
+
+func TestCountdownNeverBlocksTimer(t *testing.T) {
+	stalled := &stalledNotifier{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	notifier.SetDefault(stalled)
+	t.Cleanup(func() { notifier.SetDefault(notifier.Null{}) })
+
+	cfg := &config.Config{FocusDuration: 25 * time.Minute, ShortBreakDuration: 5 * time.Minute, LongBreakDuration: 15 * time.Minute, LongBreakInterval: 4, CountdownNotification: true}
+	timer := pomo.NewTimer(cfg)
+	closeCountdown := startCountdown(cfg, timer)
+
+	// The first update stalls; the timer's events must still go through.
+	timer.Start()
+	<-stalled.entered
+	events := make(chan struct{})
+	go func() {
+		defer close(events)
+		for i := 0; i < 100; i++ {
+			timer.Start()
+			timer.Stop()
+		}
+	}()
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the timer not to wait for a stalled notification")
+	}
+
+	close(stalled.release)
+	closeCountdown()
+	stalled.mu.Lock()
+	defer stalled.mu.Unlock()
+	if stalled.updates != 1 || stalled.removed != 1 {
+		t.Errorf("Expected stale updates to be dropped, got %d updates and %d removals", stalled.updates, stalled.removed)
+	}
+}
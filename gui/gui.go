@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -35,10 +36,38 @@ func getSlideshowImagePaths() []string {
 
 var currentSlideshow *SlideshowComponent // Declare outside Show function
 var timeNow = time.Now // For testing purposes
+var dataDir string     // User data directory; media placed here wins over the bundled files
 
 func Show(cfg *config.Config, myWindow fyne.Window) {
 	// This is a test comment to trigger reload
 	timer := pomo.NewTimer(cfg)
+	dataDir = cfg.Paths().DataDir
+
+	// A silent or recording output must be chosen before the first sound.
+	if out, err := player.OpenOutput(cfg.AudioOutput, os.Stdout); err != nil {
+		fmt.Println("Error selecting audio output:", err)
+	} else {
+		player.SetOutput(out)
+	}
+	cacheEventSounds(cfg)
+	applyNotifier(cfg)
+
+	// Settings are written in the background; bursts of edits become one save.
+	saver := config.NewSaver(cfg, config.DefaultSaveDelay)
+	saveErrorLabel := widget.NewLabel("")
+	saveErrorLabel.Wrapping = fyne.TextWrapWord
+	saveErrorLabel.Importance = widget.DangerImportance
+	saveErrorLabel.Hide()
+	saver.OnError(func(err error) {
+		fyne.Do(func() {
+			if err != nil {
+				saveErrorLabel.SetText(i18n.T("save_failed") + " " + err.Error())
+				saveErrorLabel.Show()
+			} else {
+				saveErrorLabel.Hide()
+			}
+		})
+	})
 
 	timerStr := binding.NewString()
 	timerStr.Set(formatTime(timer.RemainingTime))
@@ -61,6 +90,19 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	sessionLabel := widget.NewLabelWithData(sessionBinding)
 	sessionLabel.Alignment = fyne.TextAlignCenter
 
+	// Tarefa atual, usada em {task} nas notificações
+	taskBinding := binding.NewString()
+	taskBinding.Set(cfg.CurrentTask)
+	taskBinding.AddListener(binding.NewDataListener(func() {
+		task, _ := taskBinding.Get()
+		if task != cfg.CurrentTask {
+			cfg.CurrentTask = task
+			saver.Request()
+		}
+	}))
+	taskEntry := widget.NewEntryWithData(taskBinding)
+	taskEntry.SetPlaceHolder(i18n.T("task_placeholder"))
+
 	// Criar animação do tomate
 	tomatoEmoji := "🍅"
 	tomatoText := canvas.NewText(tomatoEmoji, color.RGBA{255, 100, 100, 255})
@@ -72,29 +114,156 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	meditationIcon.TextSize = 48
 	meditationIcon.Alignment = fyne.TextAlignCenter
 
-	startButton := widget.NewButtonWithIcon("▶️ "+i18n.T("start"), theme.MediaPlayIcon(), func() {
+	// Playlist de uma pasta, tocada durante o foco e pausada nas pausas
+	playlist := player.NewPlaylist()
+	playlistLabel := widget.NewLabel("")
+	playlistLabel.Alignment = fyne.TextAlignCenter
+	playlistLabel.Truncation = fyne.TextTruncateEllipsis
+	playlistLabel.Hide()
+	playlist.OnChange(func(file string) {
+		fyne.Do(func() {
+			if file == "" {
+				playlistLabel.Hide()
+				return
+			}
+			playlistLabel.SetText("🎵 " + strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+			playlistLabel.Show()
+		})
+	})
+	configurePlaylist := func() {
+		playlist.SetShuffle(cfg.PlaylistShuffle)
+		playlist.SetLoop(cfg.PlaylistLoop)
+		playlist.SetCrossfade(cfg.PlaylistCrossfadeDuration)
+	}
+	loadPlaylist := func() {
+		configurePlaylist()
+		if cfg.PlaylistDir == "" {
+			playlist.Stop()
+			return
+		}
+		if err := playlist.Load(cfg.PlaylistDir); err != nil {
+			fmt.Println("Error loading playlist:", err)
+		}
+	}
+	loadPlaylist()
+
+	// Ruído de fundo durante o foco; nas pausas ele para ou fica mais baixo
+	noisePlayer := player.NewNoisePlayer()
+	updateAmbience := func() {
+		noise := player.Noise(cfg.FocusNoise)
+		switch {
+		case !timer.IsRunning:
+			noisePlayer.Stop()
+		case timer.State == pomo.Pomodoro:
+			if err := noisePlayer.Play(noise); err != nil {
+				fmt.Println("Error playing noise:", err)
+			}
+		case cfg.FocusNoiseInBreaks:
+			noisePlayer.Duck()
+		default:
+			noisePlayer.Stop()
+		}
+
+		if timer.IsRunning && timer.State == pomo.Pomodoro && cfg.PlaylistDir != "" {
+			if err := playlist.Play(); err != nil {
+				fmt.Println("Error playing playlist:", err)
+			}
+		} else {
+			playlist.Pause()
+		}
+	}
+
+	// Tique-taque do relógio, em sincronia com os segundos do timer
+	timer.OnEvent(func(e pomo.Event) {
+		if e.Kind != pomo.EventTick || e.State != pomo.Pomodoro || !cfg.TickEnabled || e.Remaining <= 0 {
+			return
+		}
+		if cfg.TickLastMinuteOnly && e.Remaining > time.Minute {
+			return
+		}
+		tock := cfg.TickStyle == "ticktock" && int(e.Remaining.Seconds())%2 == 1
+		if err := player.Tick(tock); err != nil {
+			fmt.Println("Error playing tick:", err)
+		}
+	})
+
+	// Notificação com a contagem regressiva, atualizada no lugar
+	closeCountdown := startCountdown(cfg, timer)
+
+	// Sinos dentro da fase, também no compasso do timer
+	timer.OnEvent(func(e pomo.Event) {
+		if e.Kind == pomo.EventTick {
+			ringBells(cfg, e, func() {
+				title, body := message(cfg, config.EventBell, messageVars(cfg, timer))
+				go notifier.Notify(title, body)
+			})
+		}
+	})
+
+	// Avisos antes do fim da fase; seguem o timer mesmo com pausa ou extensão
+	timer.OnEvent(func(e pomo.Event) {
+		if e.Kind == pomo.EventTick {
+			due := giveWarnings(cfg, e, func() {
+				vars := messageVars(cfg, timer)
+				vars["{remaining}"] = formatTime(e.Remaining)
+				title, body := message(cfg, config.EventWarning, vars)
+				go notifier.Notify(title, body)
+			})
+			if due {
+				postWebhooks(cfg, timer, config.EventWarning)
+			}
+		}
+	})
+
+	startTimer := func() {
 		if !timer.IsRunning {
 			if isInactive(cfg) {
-				notifier.Notify(i18n.T("pomodoro"), "Timer is inactive during this period.")
+				notify(cfg, timer, config.EventInactive)
 				return
 			}
 			timer.Start()
-			player.Play(getMediaPath("focar/f1.mp3"))
-			notifier.Notify(i18n.T("pomodoro"), i18n.T("time_to_focus"))
+			updateAmbience()
+			playEventSound(cfg, phaseStartEvent(timer.State))
+			notify(cfg, timer, phaseStartEvent(timer.State))
+			postWebhooks(cfg, timer, phaseStartEvent(timer.State))
 		}
-	})
+	}
+	startButton := widget.NewButtonWithIcon("▶️ "+i18n.T("start"), theme.MediaPlayIcon(), startTimer)
+
+	// Botões da notificação de fim de fase; chegam de outra goroutine
+	skipPhase := func() {
+		fyne.Do(func() {
+			timer.NextState()
+			timer.Start()
+			updateAmbience()
+			playEventSound(cfg, phaseStartEvent(timer.State))
+			postWebhooks(cfg, timer, phaseStartEvent(timer.State))
+		})
+	}
+	extendPhase := func() {
+		fyne.Do(func() {
+			timer.Extend(extendBy)
+			updateAmbience()
+			timerStr.Set(formatTime(timer.RemainingTime))
+		})
+	}
 
 	pauseButton := widget.NewButtonWithIcon("⏸️ "+i18n.T("pause"), theme.MediaPauseIcon(), func() {
 		if timer.IsRunning {
 			timer.Stop()
-			notifier.Notify(i18n.T("pomodoro"), "Timer paused!")
+			updateAmbience()
+			playEventSound(cfg, config.EventPause)
+			notify(cfg, timer, config.EventPause)
+			postWebhooks(cfg, timer, config.EventPause)
 		}
 	})
 
 	resetButton := widget.NewButtonWithIcon("🔄 "+i18n.T("stop"), theme.MediaReplayIcon(), func() {
 		timer.Reset()
+		updateAmbience()
 		timerStr.Set(formatTime(timer.RemainingTime))
-		notifier.Notify(i18n.T("pomodoro"), "Timer reset!")
+		notify(cfg, timer, config.EventReset)
+		postWebhooks(cfg, timer, config.EventReset)
 	})
 
 	go func() {
@@ -112,12 +281,22 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 			sessionBinding.Set(newSessionText)
 
 			if timer.RemainingTime <= 0 {
+				previous := timer.State
 				timer.NextState()
 				if cfg.AutoStartCycles {
 					timer.Start()
 				}
-				player.Play(getMediaPath("meditar/m1.mp3"))
-				notifier.Notify(i18n.T("pomodoro"), i18n.T("time_to_break"))
+				updateAmbience()
+				event := phaseStartEvent(timer.State)
+				if previous == pomo.LongBreakState {
+					event = config.EventSessionComplete
+				}
+				announce(cfg, event, phaseAnnouncement(previous, timer))
+				postWebhooks(cfg, timer, event)
+				title, body := message(cfg, event, messageVars(cfg, timer))
+				if err := notifier.Send(phaseEndNotification(title, body, timer.State, timer.IsRunning, func() { fyne.Do(startTimer) }, skipPhase, extendPhase)); err != nil {
+					fmt.Println("Error sending notification:", err)
+				}
 			}
 		}
 	}()
@@ -196,21 +375,77 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	// Player de áudios binaurais
 	binauralPlayer := player.NewBinauralPlayer()
 
-	// Botões para áudios binaurais (apenas meditação de 5 minutos)
-	binauralButton := widget.NewButtonWithIcon("🧘 Meditação (5min)", theme.MediaPlayIcon(), func() {
-		binauralPlayer.Play(getMediaPath("binaural/meditation_5min.mp3"))
+	// Progresso da meditação, com o tempo restante
+	meditationProgress := widget.NewProgressBar()
+	meditationProgress.TextFormatter = func() string {
+		return formatTime(time.Duration(meditationProgress.Max-meditationProgress.Value) * time.Second)
+	}
+	meditationProgress.Hide()
+
+	var binauralButton, stopBinauralButton *widget.Button
+	updateMeditationControls := func() {
+		if !binauralPlayer.IsPlaying() {
+			binauralButton.Show()
+			stopBinauralButton.Hide()
+			meditationProgress.Hide()
+			return
+		}
+		binauralButton.Hide()
+		stopBinauralButton.Show()
+		if duration := binauralPlayer.Duration(); duration > 0 {
+			meditationProgress.Max = duration.Seconds()
+			meditationProgress.SetValue(min(binauralPlayer.Position().Seconds(), meditationProgress.Max))
+			meditationProgress.Show()
+		}
+	}
+	binauralPlayer.OnFinished(func() {
+		fyne.Do(updateMeditationControls)
+	})
+
+	// Botões para áudios binaurais, gerados com as frequências das configurações
+	binauralButton = widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		err := binauralPlayer.Play(player.Binaural{
+			Carrier:  cfg.MeditationCarrier,
+			Beat:     cfg.MeditationBeat,
+			Duration: cfg.MeditationDuration,
+			Fade:     cfg.MeditationFadeDuration,
+		})
+		if err != nil {
+			fmt.Println("Error playing meditation audio:", err)
+			return
+		}
+		updateMeditationControls()
+		go func() {
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
+			for range ticker.C {
+				if !binauralPlayer.IsPlaying() {
+					return
+				}
+				fyne.Do(updateMeditationControls)
+			}
+		}()
 	})
+	updateBinauralButton := func() {
+		binauralButton.SetText(fmt.Sprintf(i18n.T("meditation_minutes"), int(cfg.MeditationDuration.Minutes())))
+	}
+	updateBinauralButton()
 
-	stopBinauralButton := widget.NewButtonWithIcon("⏹️ Parar", theme.MediaStopIcon(), func() {
+	stopBinauralButton = widget.NewButtonWithIcon(i18n.T("stop"), theme.MediaStopIcon(), func() {
 		binauralPlayer.Stop()
+		updateMeditationControls()
 	})
+	stopBinauralButton.Hide()
 
 	// Container para controles de áudio binaural
-	binauralControls := container.NewHBox(
-		layout.NewSpacer(),
-		binauralButton,
-		stopBinauralButton,
-		layout.NewSpacer(),
+	binauralControls := container.NewVBox(
+		container.NewHBox(
+			layout.NewSpacer(),
+			binauralButton,
+			stopBinauralButton,
+			layout.NewSpacer(),
+		),
+		meditationProgress,
 	)
 
 	topSpacer := canvas.NewRectangle(color.Transparent)
@@ -222,7 +457,9 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		meditationIcon,
 		timerText,
 		sessionLabel,
+		taskEntry,
 		buttons,
+		playlistLabel,
 		binauralControls,
 	)
 
@@ -271,7 +508,7 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	animationBinding.AddListener(binding.NewDataListener(func() {
 		val, _ := animationBinding.Get()
 		cfg.Animation = val
-		cfg.Save()
+		saver.Request()
 		updatePomodoroTab()
 	}))
 
@@ -294,14 +531,14 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	startOnLaunchBinding.Set(cfg.StartOnLaunch)
 	startOnLaunchBinding.AddListener(binding.NewDataListener(func() {
 		cfg.StartOnLaunch, _ = startOnLaunchBinding.Get()
-		cfg.Save()
+		saver.Request()
 	}))
 
 	autoStartCyclesBinding := binding.NewBool()
 	autoStartCyclesBinding.Set(cfg.AutoStartCycles)
 	autoStartCyclesBinding.AddListener(binding.NewDataListener(func() {
 		cfg.AutoStartCycles, _ = autoStartCyclesBinding.Get()
-		cfg.Save()
+		saver.Request()
 	}))
 
 	// --- Inactive Period Bindings and UI ---
@@ -347,7 +584,7 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	inactiveEnabled1Binding.Set(cfg.InactiveEnabled1)
 	inactiveEnabled1Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveEnabled1, _ = inactiveEnabled1Binding.Get()
-		cfg.Save()
+		saver.Request()
 	}))
 
 	inactiveStart1Binding := binding.NewString()
@@ -358,13 +595,13 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 
 	inactiveStart1Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveStart1, _ = inactiveStart1Binding.Get()
-		cfg.Save()
+		saver.Request()
 		checkTimeValidity(inactiveStart1Binding, validIcon1s)
 		checkOvernight(inactiveStart1Binding, inactiveEnd1Binding, nextDayLabel1)
 	}))
 	inactiveEnd1Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveEnd1, _ = inactiveEnd1Binding.Get()
-		cfg.Save()
+		saver.Request()
 		checkTimeValidity(inactiveEnd1Binding, validIcon1e)
 		checkOvernight(inactiveStart1Binding, inactiveEnd1Binding, nextDayLabel1)
 	}))
@@ -373,7 +610,7 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	inactiveEnabled2Binding.Set(cfg.InactiveEnabled2)
 	inactiveEnabled2Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveEnabled2, _ = inactiveEnabled2Binding.Get()
-		cfg.Save()
+		saver.Request()
 	}))
 
 	inactiveStart2Binding := binding.NewString()
@@ -384,13 +621,13 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 
 	inactiveStart2Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveStart2, _ = inactiveStart2Binding.Get()
-		cfg.Save()
+		saver.Request()
 		checkTimeValidity(inactiveStart2Binding, validIcon2s)
 		checkOvernight(inactiveStart2Binding, inactiveEnd2Binding, nextDayLabel2)
 	}))
 	inactiveEnd2Binding.AddListener(binding.NewDataListener(func() {
 		cfg.InactiveEnd2, _ = inactiveEnd2Binding.Get()
-		cfg.Save()
+		saver.Request()
 		checkTimeValidity(inactiveEnd2Binding, validIcon2e)
 		checkOvernight(inactiveStart2Binding, inactiveEnd2Binding, nextDayLabel2)
 	}))
@@ -401,7 +638,7 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		val, _ := focusDurationBinding.Get()
 		mins, _ := strconv.Atoi(val)
 		cfg.FocusDuration = time.Duration(mins) * time.Minute
-		cfg.Save()
+		saver.Request()
 	}))
 
 	shortBreakDurationBinding := binding.NewString()
@@ -410,7 +647,7 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		val, _ := shortBreakDurationBinding.Get()
 		mins, _ := strconv.Atoi(val)
 		cfg.ShortBreakDuration = time.Duration(mins) * time.Minute
-		cfg.Save()
+		saver.Request()
 	}))
 
 	longBreakDurationBinding := binding.NewString()
@@ -419,9 +656,215 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		val, _ := longBreakDurationBinding.Get()
 		mins, _ := strconv.Atoi(val)
 		cfg.LongBreakDuration = time.Duration(mins) * time.Minute
-		cfg.Save()
+		saver.Request()
+	}))
+
+	// Volumes are shown in percent and stored as 0..1.
+	newVolumeBinding := func(value *float64, apply func(float64)) binding.Float {
+		b := binding.NewFloat()
+		b.Set(*value * 100)
+		b.AddListener(binding.NewDataListener(func() {
+			v, _ := b.Get()
+			*value = v / 100
+			apply(*value)
+			saver.Request()
+		}))
+		return b
+	}
+	masterVolumeBinding := newVolumeBinding(&cfg.MasterVolume, player.SetMasterVolume)
+	cueVolumeBinding := newVolumeBinding(&cfg.CueVolume, func(v float64) { player.SetVolume(player.Cues, v) })
+	ambienceVolumeBinding := newVolumeBinding(&cfg.AmbienceVolume, func(v float64) { player.SetVolume(player.Ambience, v) })
+	meditationVolumeBinding := newVolumeBinding(&cfg.MeditationVolume, func(v float64) { player.SetVolume(player.Meditation, v) })
+	tickVolumeBinding := newVolumeBinding(&cfg.TickVolume, func(v float64) { player.DefaultMixer().Track(player.TickTrack).SetVolume(v) })
+
+	meditationCarrierBinding := binding.NewString()
+	meditationCarrierBinding.Set(strconv.FormatFloat(cfg.MeditationCarrier, 'f', -1, 64))
+	meditationCarrierBinding.AddListener(binding.NewDataListener(func() {
+		val, _ := meditationCarrierBinding.Get()
+		hz, err := strconv.ParseFloat(val, 64)
+		if err != nil || hz <= 0 {
+			return
+		}
+		cfg.MeditationCarrier = hz
+		saver.Request()
+	}))
+
+	meditationDurationBinding := binding.NewString()
+	meditationDurationBinding.Set(fmt.Sprintf("%.0f", cfg.MeditationDuration.Minutes()))
+	meditationDurationBinding.AddListener(binding.NewDataListener(func() {
+		val, _ := meditationDurationBinding.Get()
+		mins, err := strconv.Atoi(val)
+		if err != nil || mins <= 0 {
+			return // 0 would play forever
+		}
+		d := time.Duration(mins) * time.Minute
+		if d == cfg.MeditationDuration {
+			return
+		}
+		cfg.MeditationDuration = d
+		saver.Request()
+		updateBinauralButton()
+	}))
+	meditationDurationEntry := widget.NewEntryWithData(meditationDurationBinding)
+	meditationDurationEntry.Validator = func(s string) error {
+		if mins, err := strconv.Atoi(s); err != nil || mins <= 0 {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		return nil
+	}
+
+	// Fade da sessão binaural, em segundos
+	meditationFadeBinding := binding.NewString()
+	meditationFadeBinding.Set(fmt.Sprintf("%.0f", cfg.MeditationFadeDuration.Seconds()))
+	meditationFadeBinding.AddListener(binding.NewDataListener(func() {
+		val, _ := meditationFadeBinding.Get()
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			return
+		}
+		d := time.Duration(secs) * time.Second
+		if d == cfg.MeditationFadeDuration {
+			return
+		}
+		cfg.MeditationFadeDuration = d
+		saver.Request()
+	}))
+	meditationFadeEntry := widget.NewEntryWithData(meditationFadeBinding)
+	meditationFadeEntry.Validator = func(s string) error {
+		if secs, err := strconv.Atoi(s); err != nil || secs < 0 {
+			return fmt.Errorf("invalid fade %q", s)
+		}
+		return nil
+	}
+
+	// Brainwave bands offered for the beat frequency.
+	beatBands := []struct {
+		key string
+		hz  float64
+	}{
+		{"band_alpha", player.AlphaBeat},
+		{"band_theta", player.ThetaBeat},
+		{"band_delta", player.DeltaBeat},
+	}
+	beatOptions := make([]string, len(beatBands))
+	for i, band := range beatBands {
+		beatOptions[i] = i18n.T(band.key)
+	}
+	beatSelect := widget.NewSelect(beatOptions, func(s string) {
+		for _, band := range beatBands {
+			if i18n.T(band.key) == s && cfg.MeditationBeat != band.hz {
+				cfg.MeditationBeat = band.hz
+				saver.Request()
+			}
+		}
+	})
+	selectBeatBand := func() {
+		for _, band := range beatBands {
+			if cfg.MeditationBeat == band.hz {
+				beatSelect.SetSelected(i18n.T(band.key))
+				return
+			}
+		}
+		beatSelect.ClearSelected()
+	}
+	selectBeatBand()
+
+	meditationForm := widget.NewForm(
+		widget.NewFormItem(i18n.T("meditation_carrier"), widget.NewEntryWithData(meditationCarrierBinding)),
+		widget.NewFormItem(i18n.T("meditation_beat"), beatSelect),
+		widget.NewFormItem(i18n.T("meditation_duration"), meditationDurationEntry),
+		widget.NewFormItem(i18n.T("meditation_fade"), meditationFadeEntry),
+	)
+
+	noiseOptions := []string{i18n.T("noise_off")}
+	for _, n := range player.Noises {
+		noiseOptions = append(noiseOptions, i18n.T("noise_"+string(n)))
+	}
+	noiseSelect := widget.NewSelect(noiseOptions, func(s string) {
+		noise := string(player.NoiseOff)
+		for _, n := range player.Noises {
+			if i18n.T("noise_"+string(n)) == s {
+				noise = string(n)
+			}
+		}
+		if noise != cfg.FocusNoise {
+			cfg.FocusNoise = noise
+			saver.Request()
+			updateAmbience()
+		}
+	})
+	selectNoise := func() {
+		noiseSelect.SetSelected(i18n.T("noise_" + cfg.FocusNoise))
+	}
+	selectNoise()
+
+	tickEnabledBinding := binding.NewBool()
+	tickEnabledBinding.Set(cfg.TickEnabled)
+	tickEnabledBinding.AddListener(binding.NewDataListener(func() {
+		cfg.TickEnabled, _ = tickEnabledBinding.Get()
+		saver.Request()
+	}))
+
+	tickLastMinuteBinding := binding.NewBool()
+	tickLastMinuteBinding.Set(cfg.TickLastMinuteOnly)
+	tickLastMinuteBinding.AddListener(binding.NewDataListener(func() {
+		cfg.TickLastMinuteOnly, _ = tickLastMinuteBinding.Get()
+		saver.Request()
+	}))
+
+	tickStyleRadio := widget.NewRadioGroup([]string{i18n.T("tick_single"), i18n.T("tick_tock")}, func(s string) {
+		style := "tick"
+		if s == i18n.T("tick_tock") {
+			style = "ticktock"
+		}
+		if style != cfg.TickStyle {
+			cfg.TickStyle = style
+			saver.Request()
+		}
+	})
+	tickStyleRadio.Horizontal = true
+	selectTickStyle := func() {
+		if cfg.TickStyle == "ticktock" {
+			tickStyleRadio.SetSelected(i18n.T("tick_tock"))
+		} else {
+			tickStyleRadio.SetSelected(i18n.T("tick_single"))
+		}
+	}
+	selectTickStyle()
+
+	tickForm := widget.NewForm(
+		widget.NewFormItem(i18n.T("tick_style"), tickStyleRadio),
+		widget.NewFormItem(i18n.T("tick_volume"), widget.NewSliderWithData(0, 100, tickVolumeBinding)),
+	)
+
+	noiseInBreaksBinding := binding.NewBool()
+	noiseInBreaksBinding.Set(cfg.FocusNoiseInBreaks)
+	noiseInBreaksBinding.AddListener(binding.NewDataListener(func() {
+		cfg.FocusNoiseInBreaks, _ = noiseInBreaksBinding.Get()
+		saver.Request()
+		updateAmbience()
 	}))
 
+	soundForm, reloadSounds := newSoundSettings(cfg, saver, myWindow)
+	speechForm, reloadSpeech := newSpeechSettings(cfg, saver)
+	bellForm, reloadBells := newBellSettings(cfg, saver, myWindow)
+	warningForm, reloadWarnings := newWarningSettings(cfg, saver)
+	webhookForm, reloadWebhooks := newWebhookSettings(cfg, saver)
+	notifierForm, reloadNotifier := newNotifierSettings(cfg, saver, closeCountdown)
+	messageForm, reloadMessages := newMessageSettings(cfg, saver)
+
+	playlistForm, reloadPlaylistSettings := newPlaylistSettings(cfg, saver, myWindow, func() {
+		loadPlaylist()
+		updateAmbience()
+	}, configurePlaylist)
+
+	volumeForm := widget.NewForm(
+		widget.NewFormItem(i18n.T("master_volume"), widget.NewSliderWithData(0, 100, masterVolumeBinding)),
+		widget.NewFormItem(i18n.T("cue_volume"), widget.NewSliderWithData(0, 100, cueVolumeBinding)),
+		widget.NewFormItem(i18n.T("ambience_volume"), widget.NewSliderWithData(0, 100, ambienceVolumeBinding)),
+		widget.NewFormItem(i18n.T("meditation_volume"), widget.NewSliderWithData(0, 100, meditationVolumeBinding)),
+	)
+
 	// Create Entry widgets and disable their default validators
 	inactiveStart1Entry := widget.NewEntryWithData(inactiveStart1Binding)
 	inactiveStart1Entry.Validator = nil
@@ -448,7 +891,53 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		widget.NewFormItem(i18n.T("long_break_duration"), widget.NewEntryWithData(longBreakDurationBinding)),
 	)
 
+	// Push the current config values back into the widgets, e.g. after an import.
+	reloadSettings := func() {
+		startOnLaunchBinding.Set(cfg.StartOnLaunch)
+		autoStartCyclesBinding.Set(cfg.AutoStartCycles)
+		if cfg.Animation == "icons" {
+			animationRadio.SetSelected(i18n.T("icons"))
+		} else {
+			animationRadio.SetSelected(i18n.T("slideshow"))
+		}
+		inactiveEnabled1Binding.Set(cfg.InactiveEnabled1)
+		inactiveStart1Binding.Set(cfg.InactiveStart1)
+		inactiveEnd1Binding.Set(cfg.InactiveEnd1)
+		inactiveEnabled2Binding.Set(cfg.InactiveEnabled2)
+		inactiveStart2Binding.Set(cfg.InactiveStart2)
+		inactiveEnd2Binding.Set(cfg.InactiveEnd2)
+		focusDurationBinding.Set(fmt.Sprintf("%.0f", cfg.FocusDuration.Minutes()))
+		shortBreakDurationBinding.Set(fmt.Sprintf("%.0f", cfg.ShortBreakDuration.Minutes()))
+		longBreakDurationBinding.Set(fmt.Sprintf("%.0f", cfg.LongBreakDuration.Minutes()))
+		masterVolumeBinding.Set(cfg.MasterVolume * 100)
+		cueVolumeBinding.Set(cfg.CueVolume * 100)
+		ambienceVolumeBinding.Set(cfg.AmbienceVolume * 100)
+		meditationVolumeBinding.Set(cfg.MeditationVolume * 100)
+		meditationCarrierBinding.Set(strconv.FormatFloat(cfg.MeditationCarrier, 'f', -1, 64))
+		meditationDurationBinding.Set(fmt.Sprintf("%.0f", cfg.MeditationDuration.Minutes()))
+		meditationFadeBinding.Set(fmt.Sprintf("%.0f", cfg.MeditationFadeDuration.Seconds()))
+		selectBeatBand()
+		selectNoise()
+		noiseInBreaksBinding.Set(cfg.FocusNoiseInBreaks)
+		tickEnabledBinding.Set(cfg.TickEnabled)
+		tickLastMinuteBinding.Set(cfg.TickLastMinuteOnly)
+		tickVolumeBinding.Set(cfg.TickVolume * 100)
+		selectTickStyle()
+		reloadSounds()
+		reloadSpeech()
+		reloadBells()
+		reloadWarnings()
+		reloadNotifier()
+		reloadMessages()
+		reloadWebhooks()
+		taskBinding.Set(cfg.CurrentTask)
+		reloadPlaylistSettings()
+		loadPlaylist()
+		updateAmbience()
+	}
+
 	settingsContent := container.NewVBox(
+		saveErrorLabel,
 		widget.NewCheckWithData(i18n.T("start_on_launch"), startOnLaunchBinding),
 		widget.NewCheckWithData(i18n.T("auto_start_cycles"), autoStartCyclesBinding),
 		widget.NewSeparator(),
@@ -464,6 +953,46 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 		widget.NewSeparator(),
 		widget.NewLabel(i18n.T("durations_in_minutes")),
 		durationForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("volume")),
+		volumeForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("sounds")),
+		soundForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("speech")),
+		speechForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("bells")),
+		bellForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("warnings")),
+		warningForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("notifiers")),
+		notifierForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("messages")),
+		messageForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("webhooks")),
+		webhookForm,
+		widget.NewSeparator(),
+		widget.NewCheckWithData(i18n.T("tick_enabled"), tickEnabledBinding),
+		tickForm,
+		widget.NewCheckWithData(i18n.T("tick_last_minute_only"), tickLastMinuteBinding),
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("focus_noise")),
+		noiseSelect,
+		widget.NewCheckWithData(i18n.T("focus_noise_in_breaks"), noiseInBreaksBinding),
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("playlist")),
+		playlistForm,
+		widget.NewSeparator(),
+		widget.NewLabel(i18n.T("meditation")),
+		meditationForm,
+		widget.NewSeparator(),
+		newSettingsBundleControls(cfg, saver, myWindow, reloadSettings),
 	)
 	settingsTab := container.NewVScroll(settingsContent)
 
@@ -499,6 +1028,12 @@ func Show(cfg *config.Config, myWindow fyne.Window) {
 	myWindow.CenterOnScreen()
 	myWindow.SetOnClosed(func() {
 		binauralPlayer.Stop()
+		noisePlayer.Stop()
+		playlist.Stop()
+		closeCountdown()
+		if err := saver.Flush(); err != nil {
+			fmt.Println("Error saving config:", err)
+		}
 	})
 	myWindow.ShowAndRun()
 }
@@ -563,7 +1098,33 @@ func updateTitle(w fyne.Window, t *pomo.Timer, inSlideshowMode bool) {
 	})
 }
 
+// phaseStartEvent is the sound event for starting a phase in state.
+func phaseStartEvent(state pomo.State) string {
+	switch state {
+	case pomo.ShortBreakState:
+		return config.EventBreakStart
+	case pomo.LongBreakState:
+		return config.EventLongBreakStart
+	default:
+		return config.EventFocusStart
+	}
+}
+
+// playSound plays a cue; a missing sound never interrupts the timer.
+func playSound(path string) {
+	if err := player.Play(path); err != nil {
+		fmt.Println("Error playing sound:", err)
+	}
+}
+
 func getMediaPath(fileName string) string {
+	if dataDir != "" {
+		userPath := filepath.Join(dataDir, "media", fileName)
+		if _, err := os.Stat(userPath); err == nil {
+			return userPath
+		}
+	}
+
 	executable, err := os.Executable()
 	if err != nil {
 		return filepath.Join("media", fileName)
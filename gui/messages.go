@@ -0,0 +1,106 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/notifier"
+	"pomodoro-do-ben/pomo"
+)
+
+// messageVars returns the values of the placeholders of notification
+// templates for the timer as it is now.
+func messageVars(cfg *config.Config, timer *pomo.Timer) map[string]string {
+	cycle := timer.Completed()
+	if timer.State == pomo.Pomodoro {
+		cycle++ // the pomodoro under way
+	}
+	return map[string]string{
+		"{phase}":     i18n.T("phase_" + statePhase(timer.State)),
+		"{next}":      i18n.T("phase_" + statePhase(timer.Next())),
+		"{cycle}":     fmt.Sprintf("%d/%d", cycle, cfg.LongBreakInterval),
+		"{task}":      cfg.CurrentTask,
+		"{remaining}": formatTime(timer.RemainingTime),
+	}
+}
+
+// message returns the title and body of the notification of event, from
+// the templates in cfg or the translated defaults.
+func message(cfg *config.Config, event string, vars map[string]string) (title, body string) {
+	m := cfg.Message(event)
+	if m.Title == "" {
+		m.Title = "notify_title"
+	}
+	if m.Body == "" {
+		m.Body = "notify_" + event
+	}
+	return expandPlaceholders(i18n.T(m.Title), vars), expandPlaceholders(i18n.T(m.Body), vars)
+}
+
+func expandPlaceholders(template string, vars map[string]string) string {
+	var pairs []string
+	for placeholder, value := range vars {
+		pairs = append(pairs, placeholder, value)
+	}
+	return strings.TrimSpace(strings.NewReplacer(pairs...).Replace(template))
+}
+
+// notify shows the plain notification of event.
+func notify(cfg *config.Config, timer *pomo.Timer, event string) {
+	notifier.Notify(message(cfg, event, messageVars(cfg, timer)))
+}
+
+// newMessageSettings returns the Settings rows editing the text of each
+// notification and a function that refreshes them from cfg.
+func newMessageSettings(cfg *config.Config, saver *config.Saver) (fyne.CanvasObject, func()) {
+	form := widget.NewForm()
+	var reloads []func()
+
+	for _, event := range config.MessageEvents {
+		event := event
+
+		titleEntry := widget.NewEntry()
+		titleEntry.SetPlaceHolder(i18n.T("notify_title"))
+		bodyEntry := widget.NewEntry()
+		bodyEntry.SetPlaceHolder(i18n.T("notify_" + event))
+		loading := false
+		changed := func(string) {
+			if loading {
+				return
+			}
+			m := config.Message{Title: strings.TrimSpace(titleEntry.Text), Body: strings.TrimSpace(bodyEntry.Text)}
+			if m != cfg.Message(event) {
+				cfg.SetMessage(event, m)
+				saver.Request()
+			}
+		}
+		reload := func() {
+			m := cfg.Message(event)
+			loading = true
+			titleEntry.SetText(m.Title)
+			bodyEntry.SetText(m.Body)
+			loading = false
+		}
+		reload()
+		titleEntry.OnChanged = changed
+		bodyEntry.OnChanged = changed
+
+		form.Append(i18n.T("event_"+event), container.NewGridWithColumns(2, titleEntry, bodyEntry))
+		reloads = append(reloads, reload)
+	}
+
+	hint := widget.NewLabel(fmt.Sprintf(i18n.T("messages_hint"), strings.Join(config.Placeholders, " ")))
+	hint.Wrapping = fyne.TextWrapWord
+
+	return container.NewVBox(hint, form), func() {
+		for _, reload := range reloads {
+			reload()
+		}
+	}
+}
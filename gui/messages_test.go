@@ -0,0 +1,29 @@
+package gui
+
+import (
+	"testing"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+)
+
+func TestMessage(t *testing.T) {
+	cfg := &config.Config{}
+	vars := map[string]string{"{phase}": "Focus", "{cycle}": "2/4", "{task}": ""}
+
+	title, body := message(cfg, config.EventFocusStart, vars)
+	if want := expandPlaceholders(i18n.T("notify_title"), vars); title != want {
+		t.Errorf("Expected default title %q, got %q", want, title)
+	}
+	if want := expandPlaceholders(i18n.T("notify_focus_start"), vars); body != want {
+		t.Errorf("Expected default body %q, got %q", want, body)
+	}
+
+	cfg.SetMessage(config.EventFocusStart, config.Message{Body: "Pomodoro {cycle}: {task}"})
+	if _, body = message(cfg, config.EventFocusStart, vars); body != "Pomodoro 2/4:" {
+		t.Errorf("Expected the template expanded and trimmed, got %q", body)
+	}
+	if _, body = message(cfg, config.EventBreakStart, vars); body != i18n.T("notify_break_start") {
+		t.Errorf("Expected other events to keep their default, got %q", body)
+	}
+}
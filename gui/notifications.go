@@ -0,0 +1,134 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/notifier"
+	"pomodoro-do-ben/pomo"
+)
+
+// extendBy is how much longer the "+5 min" button keeps the last phase going.
+const extendBy = 5 * time.Minute
+
+// phaseEndNotification is shown when the timer moves to state. Its buttons
+// start the new phase if it is waiting, skip it, or go back to the phase that
+// ended for a few more minutes.
+func phaseEndNotification(title, body string, state pomo.State, running bool, start, skip, extend func()) notifier.Notification {
+	n := notifier.Notification{
+		Title:    title,
+		Body:     body,
+		Category: "x-pomodoro.phase",
+	}
+	startLabel := i18n.T("action_start_break")
+	if state == pomo.Pomodoro {
+		startLabel = i18n.T("action_start_focus")
+	}
+	if !running {
+		// Nothing happens until the user answers, so keep it up.
+		n.Timeout = notifier.Persistent
+		n.Actions = append(n.Actions, notifier.Action{Label: startLabel, Do: start})
+	}
+	n.Actions = append(n.Actions,
+		notifier.Action{Label: i18n.T("action_skip"), Do: skip},
+		notifier.Action{Label: i18n.T("action_extend"), Do: extend},
+	)
+	return n
+}
+
+// applyNotifier makes the notifier chosen in cfg the one every notification
+// goes through.
+func applyNotifier(cfg *config.Config) {
+	notifier.SetDefault(notifier.Open(cfg.Notifier, fyne.CurrentApp()))
+}
+
+// newNotifierSettings returns the Settings rows choosing how notifications
+// are shown and a function that refreshes them from cfg. closeCountdown is
+// called when the countdown notification is turned off.
+func newNotifierSettings(cfg *config.Config, saver *config.Saver, closeCountdown func()) (fyne.CanvasObject, func()) {
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	showStatus := func() {
+		status.SetText(fmt.Sprintf(i18n.T("notifier_using"), notifier.Default().Name()))
+	}
+	showStatus()
+
+	var labels []string
+	for _, name := range notifier.Names {
+		labels = append(labels, i18n.T("notifier_"+name))
+	}
+	applied := cfg.Notifier
+	choose := func(name string) {
+		if name == applied {
+			return
+		}
+		cfg.Notifier, applied = name, name
+		applyNotifier(cfg)
+		showStatus()
+	}
+	sel := widget.NewSelect(labels, func(s string) {
+		if i := slices.Index(labels, s); i >= 0 && notifier.Names[i] != cfg.Notifier {
+			choose(notifier.Names[i])
+			saver.Request()
+		}
+	})
+	selectCurrent := func() {
+		if i := slices.Index(notifier.Names, cfg.Notifier); i >= 0 {
+			sel.SetSelected(labels[i])
+		} else {
+			sel.SetSelected(labels[0])
+		}
+	}
+	selectCurrent()
+
+	testButton := widget.NewButton(i18n.T("notifier_test"), func() {
+		go func() {
+			err := notifier.Send(notifier.Notification{Title: i18n.T("pomodoro"), Body: i18n.T("notifier_test_message")})
+			fyne.Do(func() {
+				if err != nil {
+					status.SetText(fmt.Sprintf(i18n.T("notifier_failed"), err))
+				} else {
+					showStatus()
+				}
+			})
+		}()
+	})
+
+	countdownBinding := binding.NewBool()
+	countdownBinding.Set(cfg.CountdownNotification)
+	countdownBinding.AddListener(binding.NewDataListener(func() {
+		on, _ := countdownBinding.Get()
+		if on == cfg.CountdownNotification {
+			return
+		}
+		cfg.CountdownNotification = on
+		saver.Request()
+		if !on {
+			closeCountdown()
+		}
+	}))
+
+	content := container.NewVBox(
+		sel,
+		container.NewBorder(nil, nil, nil, testButton, status),
+		widget.NewCheckWithData(i18n.T("countdown_notification"), countdownBinding),
+	)
+	return content, func() {
+		// An import may have chosen another notifier.
+		if cfg.Notifier != applied {
+			applied = cfg.Notifier
+			applyNotifier(cfg)
+			showStatus()
+		}
+		selectCurrent()
+		countdownBinding.Set(cfg.CountdownNotification)
+	}
+}
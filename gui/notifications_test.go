@@ -0,0 +1,33 @@
+package gui
+
+import (
+	"testing"
+
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/notifier"
+	"pomodoro-do-ben/pomo"
+)
+
+func TestPhaseEndNotification(t *testing.T) {
+	var clicked string
+	action := func(name string) func() { return func() { clicked = name } }
+
+	n := phaseEndNotification("Pomodoro", "Break", pomo.ShortBreakState, false, action("start"), action("skip"), action("extend"))
+	if n.Timeout != notifier.Persistent {
+		t.Error("Expected a waiting phase to keep its notification up")
+	}
+	if len(n.Actions) != 3 || n.Actions[0].Label != i18n.T("action_start_break") {
+		t.Fatalf("Expected start, skip and extend buttons, got %+v", n.Actions)
+	}
+	for i, name := range []string{"start", "skip", "extend"} {
+		n.Actions[i].Do()
+		if clicked != name {
+			t.Errorf("Expected button %d to %s, got %s", i, name, clicked)
+		}
+	}
+
+	n = phaseEndNotification("Pomodoro", "Focus", pomo.Pomodoro, true, action("start"), action("skip"), action("extend"))
+	if n.Body != "Focus" || len(n.Actions) != 2 {
+		t.Errorf("Expected a focus notification with skip and extend only, got %+v", n)
+	}
+}
@@ -0,0 +1,97 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+)
+
+// newPlaylistSettings returns the Settings rows of the ambient playlist and a
+// function that refreshes them from cfg. folderChanged is called after a new
+// folder is chosen or cleared, optionsChanged after the other options change.
+func newPlaylistSettings(cfg *config.Config, saver *config.Saver, w fyne.Window, folderChanged, optionsChanged func()) (fyne.CanvasObject, func()) {
+	folderLabel := widget.NewLabel("")
+	folderLabel.Truncation = fyne.TextTruncateEllipsis
+	showFolder := func() {
+		if cfg.PlaylistDir == "" {
+			folderLabel.SetText(i18n.T("playlist_no_folder"))
+		} else {
+			folderLabel.SetText(cfg.PlaylistDir)
+		}
+	}
+	showFolder()
+
+	setFolder := func(dir string) {
+		cfg.PlaylistDir = dir
+		saver.Request()
+		showFolder()
+		folderChanged()
+	}
+	chooseButton := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err != nil || dir == nil {
+				return
+			}
+			setFolder(dir.Path())
+		}, w)
+	})
+	clearButton := widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		setFolder("")
+	})
+
+	shuffleBinding := binding.NewBool()
+	shuffleBinding.Set(cfg.PlaylistShuffle)
+	shuffleBinding.AddListener(binding.NewDataListener(func() {
+		cfg.PlaylistShuffle, _ = shuffleBinding.Get()
+		saver.Request()
+		optionsChanged()
+	}))
+
+	loopBinding := binding.NewBool()
+	loopBinding.Set(cfg.PlaylistLoop)
+	loopBinding.AddListener(binding.NewDataListener(func() {
+		cfg.PlaylistLoop, _ = loopBinding.Get()
+		saver.Request()
+		optionsChanged()
+	}))
+
+	crossfadeBinding := binding.NewString()
+	crossfadeBinding.Set(fmt.Sprintf("%.0f", cfg.PlaylistCrossfadeDuration.Seconds()))
+	crossfadeBinding.AddListener(binding.NewDataListener(func() {
+		val, _ := crossfadeBinding.Get()
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			return
+		}
+		cfg.PlaylistCrossfadeDuration = time.Duration(secs) * time.Second
+		saver.Request()
+		optionsChanged()
+	}))
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("playlist_folder"), container.NewBorder(nil, nil, nil, container.NewHBox(chooseButton, clearButton), folderLabel)),
+		widget.NewFormItem(i18n.T("playlist_crossfade"), widget.NewEntryWithData(crossfadeBinding)),
+	)
+	content := container.NewVBox(
+		form,
+		widget.NewCheckWithData(i18n.T("playlist_shuffle"), shuffleBinding),
+		widget.NewCheckWithData(i18n.T("playlist_loop"), loopBinding),
+	)
+
+	return content, func() {
+		showFolder()
+		shuffleBinding.Set(cfg.PlaylistShuffle)
+		loopBinding.Set(cfg.PlaylistLoop)
+		crossfadeBinding.Set(fmt.Sprintf("%.0f", cfg.PlaylistCrossfadeDuration.Seconds()))
+	}
+}
@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+)
+
+// newSettingsBundleControls returns the Export/Import buttons of the Settings
+// tab. reload is called after an import so the widgets show the new values.
+func newSettingsBundleControls(cfg *config.Config, saver *config.Saver, w fyne.Window, reload func()) fyne.CanvasObject {
+	exportButton := widget.NewButtonWithIcon(i18n.T("export_settings"), theme.DocumentSaveIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			path := writer.URI().Path()
+			writer.Close()
+			if err := cfg.Export(path); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			dialog.ShowInformation(i18n.T("export_settings"), i18n.T("settings_exported"), w)
+		}, w)
+		saveDialog.SetFileName("pomodoro-settings.json")
+		saveDialog.Show()
+	})
+
+	importButton := widget.NewButtonWithIcon(i18n.T("import_settings"), theme.FolderOpenIcon(), func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+
+			imported, err := cfg.ReadBundle(path)
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			saved, err := cfg.Saved()
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			changes := saved.Diff(imported)
+			if len(changes) == 0 {
+				dialog.ShowInformation(i18n.T("import_settings"), i18n.T("import_no_changes"), w)
+				return
+			}
+
+			lines := make([]string, len(changes))
+			for i, change := range changes {
+				lines[i] = change.String()
+			}
+			preview := widget.NewLabel(strings.Join(lines, "\n"))
+			preview.Wrapping = fyne.TextWrapWord
+			scroll := container.NewVScroll(preview)
+			scroll.SetMinSize(fyne.NewSize(320, 200))
+			content := container.NewBorder(widget.NewLabel(fmt.Sprintf(i18n.T("import_preview"), len(changes))), nil, nil, nil, scroll)
+
+			dialog.ShowCustomConfirm(i18n.T("import_settings"), i18n.T("apply"), i18n.T("cancel"), content, func(apply bool) {
+				if !apply {
+					return
+				}
+				if err := cfg.Import(imported); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				reload()
+				saver.Request()
+			}, w)
+		}, w)
+	})
+
+	return container.NewHBox(layout.NewSpacer(), exportButton, importButton, layout.NewSpacer())
+}
@@ -0,0 +1,155 @@
+package gui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/player"
+)
+
+// builtinSounds are the sounds shipped in the media folder, by the name used
+// in the config.
+var builtinSounds = []struct {
+	name string
+	file string
+}{
+	{"focus", "focar/f1.mp3"},
+	{"break", "meditar/m1.mp3"},
+}
+
+// soundFile returns the file to play for a configured sound, or "" for none.
+func soundFile(sound string) string {
+	if name, ok := config.BuiltinSound(sound); ok {
+		for _, b := range builtinSounds {
+			if b.name == name {
+				return getMediaPath(b.file)
+			}
+		}
+		return ""
+	}
+	return sound
+}
+
+// playEventSound plays the sound configured for event, if any.
+func playEventSound(cfg *config.Config, event string) {
+	if file := soundFile(cfg.Sound(event)); file != "" {
+		playSound(file)
+	}
+}
+
+// cacheEventSounds decodes the sounds of every event and bell into memory
+// in the background, so cues start at once when they are due.
+func cacheEventSounds(cfg *config.Config) {
+	var files []string
+	for _, event := range config.Events {
+		files = append(files, soundFile(cfg.Sound(event)))
+	}
+	for _, b := range cfg.Bells {
+		files = append(files, soundFile(b.Sound))
+	}
+	go func() {
+		if err := player.CacheSounds(files...); err != nil {
+			fmt.Println("Error caching sounds:", err)
+		}
+	}()
+}
+
+// newSoundPicker returns a row choosing a sound: none, a built-in sound or a
+// file, with a button to hear it. get returns the current sound and set
+// stores a new one. The returned function refreshes the row from get.
+func newSoundPicker(get func() string, set func(sound string), w fyne.Window) (fyne.CanvasObject, func()) {
+	// Built-in choices first, then the custom file if there is one.
+	var sel *widget.Select
+	refresh := func() {
+		options := []string{i18n.T("sound_none")}
+		for _, b := range builtinSounds {
+			options = append(options, i18n.T("sound_"+b.name))
+		}
+		selected := options[0]
+		sound := get()
+		if name, ok := config.BuiltinSound(sound); ok {
+			selected = i18n.T("sound_" + name)
+		} else if sound != config.SoundNone {
+			selected = "📄 " + filepath.Base(sound)
+			options = append(options, selected)
+		}
+		sel.Options = options
+		sel.Selected = selected
+		sel.Refresh()
+	}
+	sel = widget.NewSelect(nil, func(s string) {
+		sound := get()
+		switch {
+		case s == i18n.T("sound_none"):
+			sound = config.SoundNone
+		case s == "📄 "+filepath.Base(sound):
+			// The custom file is still selected.
+		default:
+			for _, b := range builtinSounds {
+				if s == i18n.T("sound_"+b.name) {
+					sound = config.BuiltinSoundPrefix + b.name
+				}
+			}
+		}
+		if sound != get() {
+			set(sound)
+			refresh()
+		}
+	})
+	refresh()
+
+	browseButton := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			set(path)
+			refresh()
+		}, w)
+		open.SetFilter(storage.NewExtensionFileFilter(player.Extensions))
+		open.Show()
+	})
+	previewButton := widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {
+		if file := soundFile(get()); file != "" {
+			playSound(file)
+		}
+	})
+
+	return container.NewBorder(nil, nil, nil, container.NewHBox(browseButton, previewButton), sel), refresh
+}
+
+// newSoundSettings returns the Settings rows choosing the sound of each
+// event, and a function that refreshes them from cfg.
+func newSoundSettings(cfg *config.Config, saver *config.Saver, w fyne.Window) (fyne.CanvasObject, func()) {
+	form := widget.NewForm()
+	var reloads []func()
+
+	for _, event := range config.Events {
+		event := event
+		row, refresh := newSoundPicker(func() string { return cfg.Sound(event) }, func(sound string) {
+			cfg.SetSound(event, sound)
+			saver.Request()
+			cacheEventSounds(cfg)
+		}, w)
+		form.Append(i18n.T("event_"+event), row)
+		reloads = append(reloads, refresh)
+	}
+
+	return form, func() {
+		for _, reload := range reloads {
+			reload()
+		}
+		cacheEventSounds(cfg)
+	}
+}
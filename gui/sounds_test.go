@@ -0,0 +1,57 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/player"
+	"pomodoro-do-ben/pomo"
+)
+
+// silentWAV is a valid WAV file with no audio.
+var silentWAV = []byte("RIFF\x24\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x02\x00\x44\xac\x00\x00\x10\xb1\x02\x00\x04\x00\x10\x00data\x00\x00\x00\x00")
+
+// recorder is the output of the default mixer in every test; it can only be
+// set once.
+var recorder = sync.OnceValue(func() *player.Recorder {
+	rec := player.NewRecorder()
+	player.SetOutput(rec)
+	return rec
+})
+
+func TestPhaseStartCues(t *testing.T) {
+	rec := recorder()
+
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	for _, event := range config.Events {
+		file := filepath.Join(dir, event+".wav")
+		if err := os.WriteFile(file, silentWAV, 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg.SetSound(event, file)
+	}
+
+	tests := []struct {
+		state    pomo.State
+		expected string
+	}{
+		{pomo.Pomodoro, config.EventFocusStart},
+		{pomo.ShortBreakState, config.EventBreakStart},
+		{pomo.LongBreakState, config.EventLongBreakStart},
+	}
+	for _, tt := range tests {
+		playEventSound(cfg, phaseStartEvent(tt.state))
+		events := rec.Events()
+		if len(events) == 0 {
+			t.Fatalf("Expected a cue for state %v", tt.state)
+		}
+		last := events[len(events)-1]
+		if want := filepath.Join(dir, tt.expected+".wav"); last.Kind != player.EventPlay || last.Track != player.CueTrack || last.Sound != want {
+			t.Errorf("State %v: expected %s played on the cue track, got %v", tt.state, want, last)
+		}
+	}
+}
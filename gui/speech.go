@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/pomo"
+	"pomodoro-do-ben/speech"
+)
+
+func speechOptions(cfg *config.Config) speech.Options {
+	return speech.Options{Rate: cfg.SpeechRate, Voice: cfg.SpeechVoice, Lang: i18n.Lang()}
+}
+
+// announce speaks text when announcements are on, and plays the sound of
+// event instead when they are off or cannot be spoken.
+func announce(cfg *config.Config, event, text string) {
+	if !cfg.SpeechEnabled {
+		playEventSound(cfg, event)
+		return
+	}
+	engine, err := speech.Find(speechOptions(cfg))
+	if err != nil {
+		fmt.Println("Error announcing:", err)
+		playEventSound(cfg, event)
+		return
+	}
+	go func() {
+		if err := engine.Speak(text); err != nil {
+			fmt.Println("Error announcing:", err)
+			playEventSound(cfg, event)
+		}
+	}()
+}
+
+// phaseAnnouncement is what is said when the timer moves from previous to
+// the phase t is now in.
+func phaseAnnouncement(previous pomo.State, t *pomo.Timer) string {
+	minutes := int(t.Duration.Minutes())
+	switch {
+	case previous == pomo.LongBreakState:
+		return fmt.Sprintf(i18n.T("announce_session_complete"), minutes)
+	case t.State == pomo.ShortBreakState:
+		return fmt.Sprintf(i18n.T("announce_break"), minutes)
+	case t.State == pomo.LongBreakState:
+		return fmt.Sprintf(i18n.T("announce_long_break"), minutes)
+	default:
+		return fmt.Sprintf(i18n.T("announce_focus"), minutes)
+	}
+}
+
+// newSpeechSettings returns the Settings rows of spoken announcements and a
+// function that refreshes them from cfg.
+func newSpeechSettings(cfg *config.Config, saver *config.Saver) (fyne.CanvasObject, func()) {
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	showStatus := func() {
+		engine, err := speech.Find(speechOptions(cfg))
+		if err != nil {
+			status.SetText(i18n.T("speech_no_engine"))
+		} else {
+			status.SetText(fmt.Sprintf(i18n.T("speech_engine"), engine.Name()))
+		}
+	}
+	showStatus()
+
+	enabledBinding := binding.NewBool()
+	enabledBinding.Set(cfg.SpeechEnabled)
+	enabledBinding.AddListener(binding.NewDataListener(func() {
+		cfg.SpeechEnabled, _ = enabledBinding.Get()
+		saver.Request()
+	}))
+
+	rateBinding := binding.NewString()
+	rateBinding.Set(strconv.Itoa(cfg.SpeechRate))
+	rateBinding.AddListener(binding.NewDataListener(func() {
+		val, _ := rateBinding.Get()
+		rate, err := strconv.Atoi(val)
+		if err != nil || rate <= 0 {
+			return
+		}
+		cfg.SpeechRate = rate
+		saver.Request()
+	}))
+
+	voiceBinding := binding.NewString()
+	voiceBinding.Set(cfg.SpeechVoice)
+	voiceBinding.AddListener(binding.NewDataListener(func() {
+		cfg.SpeechVoice, _ = voiceBinding.Get()
+		saver.Request()
+	}))
+	voiceEntry := widget.NewEntryWithData(voiceBinding)
+	voiceEntry.SetPlaceHolder(i18n.Lang())
+
+	testButton := widget.NewButtonWithIcon(i18n.T("speech_test"), theme.MediaPlayIcon(), func() {
+		showStatus()
+		engine, err := speech.Find(speechOptions(cfg))
+		if err != nil {
+			return
+		}
+		go func() {
+			if err := engine.Speak(fmt.Sprintf(i18n.T("announce_break"), 5)); err != nil {
+				fmt.Println("Error announcing:", err)
+			}
+		}()
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("speech_rate"), widget.NewEntryWithData(rateBinding)),
+		widget.NewFormItem(i18n.T("speech_voice"), voiceEntry),
+	)
+	content := container.NewVBox(
+		widget.NewCheckWithData(i18n.T("speech_enabled"), enabledBinding),
+		form,
+		container.NewBorder(nil, nil, nil, testButton, status),
+	)
+
+	return content, func() {
+		enabledBinding.Set(cfg.SpeechEnabled)
+		rateBinding.Set(strconv.Itoa(cfg.SpeechRate))
+		voiceBinding.Set(cfg.SpeechVoice)
+		showStatus()
+	}
+}
@@ -0,0 +1,108 @@
+package gui
+
+import (
+	"slices"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/pomo"
+)
+
+// giveWarnings gives the warnings of the current phase that are due at tick
+// e, calling notifyWarning for those with a notification, and reports whether
+// any was due. The warning sound plays once even when several warnings fall
+// on the same second.
+func giveWarnings(cfg *config.Config, e pomo.Event, notifyWarning func()) bool {
+	due, sound, notify := false, false, false
+	for _, w := range cfg.WarningsIn(statePhase(e.State)) {
+		if w.Due(e.Duration, e.Remaining) {
+			due = true
+			sound = sound || w.Sound
+			notify = notify || w.Notify
+		}
+	}
+	if sound {
+		playEventSound(cfg, config.EventWarning)
+	}
+	if notify {
+		notifyWarning()
+	}
+	return due
+}
+
+// newWarningSettings returns the Settings rows editing the warnings and a
+// function that refreshes them from cfg.
+func newWarningSettings(cfg *config.Config, saver *config.Saver) (fyne.CanvasObject, func()) {
+	list := container.NewVBox()
+
+	var phaseLabels []string
+	for _, phase := range config.Phases {
+		phaseLabels = append(phaseLabels, i18n.T("phase_"+phase))
+	}
+
+	var rebuild func()
+	rebuild = func() {
+		list.RemoveAll()
+		for i := range cfg.Warnings {
+			i := i
+			warning := &cfg.Warnings[i]
+
+			phaseSelect := widget.NewSelect(phaseLabels, nil)
+			phaseSelect.Selected = i18n.T("phase_" + warning.Phase)
+			phaseSelect.OnChanged = func(s string) {
+				warning.Phase = config.Phases[slices.Index(phaseLabels, s)]
+				saver.Request()
+			}
+
+			beforeEntry := widget.NewEntry()
+			beforeEntry.SetPlaceHolder(i18n.T("warning_before"))
+			beforeEntry.SetText(warning.Threshold())
+			beforeEntry.Validator = func(s string) error {
+				var w config.Warning
+				return w.SetThreshold(s)
+			}
+			beforeEntry.OnChanged = func(s string) {
+				if warning.SetThreshold(s) == nil {
+					saver.Request()
+				}
+			}
+
+			soundCheck := widget.NewCheck(i18n.T("warning_sound"), nil)
+			soundCheck.Checked = warning.Sound
+			soundCheck.OnChanged = func(checked bool) {
+				warning.Sound = checked
+				saver.Request()
+			}
+
+			notifyCheck := widget.NewCheck(i18n.T("warning_notify"), nil)
+			notifyCheck.Checked = warning.Notify
+			notifyCheck.OnChanged = func(checked bool) {
+				warning.Notify = checked
+				saver.Request()
+			}
+
+			removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				cfg.Warnings = slices.Delete(cfg.Warnings, i, i+1)
+				saver.Request()
+				rebuild()
+			})
+
+			list.Add(container.NewBorder(nil, nil, nil, removeButton, container.NewGridWithColumns(4, phaseSelect, beforeEntry, soundCheck, notifyCheck)))
+		}
+	}
+	rebuild()
+
+	addButton := widget.NewButtonWithIcon(i18n.T("warning_add"), theme.ContentAddIcon(), func() {
+		cfg.Warnings = append(cfg.Warnings, config.Warning{Phase: config.PhaseFocus, Before: 2 * time.Minute, Notify: true})
+		saver.Request()
+		rebuild()
+	})
+
+	return container.NewVBox(list, addButton), rebuild
+}
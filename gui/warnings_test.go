@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/player"
+	"pomodoro-do-ben/pomo"
+)
+
+func TestGiveWarnings(t *testing.T) {
+	rec := recorder()
+	file := filepath.Join(t.TempDir(), "warning.wav")
+	if err := os.WriteFile(file, silentWAV, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Sounds: map[string]string{config.EventWarning: file},
+		Warnings: []config.Warning{
+			{Phase: config.PhaseFocus, Before: 2 * time.Minute, Notify: true},
+			{Phase: config.PhaseFocus, Before: time.Minute, Sound: true},
+			{Phase: config.PhaseFocus, Before: time.Minute, Sound: true, Notify: true},
+			{Phase: config.PhaseShortBreak, Before: 30 * time.Second, Sound: true},
+		},
+	}
+
+	tests := []struct {
+		remaining time.Duration
+		plays     bool
+		notifies  bool
+	}{
+		{2*time.Minute + time.Second, false, false},
+		{2 * time.Minute, false, true},
+		{time.Minute, true, true},
+		{30 * time.Second, false, false},
+	}
+	for _, tt := range tests {
+		before := len(rec.Events())
+		notified := 0
+		giveWarnings(cfg, pomo.Event{Kind: pomo.EventTick, State: pomo.Pomodoro, Duration: 25 * time.Minute, Remaining: tt.remaining}, func() { notified++ })
+		events := rec.Events()[before:]
+		if played := len(events) == 1 && events[0].Kind == player.EventPlay && events[0].Sound == file; played != tt.plays || len(events) > 1 {
+			t.Errorf("%v remaining: expected sound %v, got events %v", tt.remaining, tt.plays, events)
+		}
+		if (notified == 1) != tt.notifies || notified > 1 {
+			t.Errorf("%v remaining: expected notification %v, got %d", tt.remaining, tt.notifies, notified)
+		}
+	}
+}
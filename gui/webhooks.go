@@ -0,0 +1,168 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/i18n"
+	"pomodoro-do-ben/pomo"
+	"pomodoro-do-ben/webhook"
+)
+
+// webhooks delivers timer events to the webhooks in the config and keeps the
+// log shown in Settings.
+var webhooks = webhook.NewSender()
+
+// webhookPayload describes event for the timer as it is now.
+func webhookPayload(cfg *config.Config, timer *pomo.Timer, event string) webhook.Payload {
+	cycle := timer.Completed()
+	if timer.State == pomo.Pomodoro {
+		cycle++ // the pomodoro under way
+	}
+	now := time.Now()
+	p := webhook.Payload{
+		Event:     event,
+		Phase:     statePhase(timer.State),
+		Next:      statePhase(timer.Next()),
+		Cycle:     fmt.Sprintf("%d/%d", cycle, cfg.LongBreakInterval),
+		Task:      cfg.CurrentTask,
+		Remaining: int(timer.RemainingTime.Seconds()),
+		Timestamp: now,
+	}
+	if timer.IsRunning {
+		ends := now.Add(timer.RemainingTime)
+		p.PhaseEndsAt = &ends
+	}
+	return p
+}
+
+// postWebhooks calls the webhooks that fire on event, in the background.
+func postWebhooks(cfg *config.Config, timer *pomo.Timer, event string) {
+	if len(cfg.Webhooks) > 0 {
+		webhooks.Send(cfg.Webhooks, webhookPayload(cfg, timer, event))
+	}
+}
+
+// newWebhookSettings returns the Settings rows editing the webhooks, with the
+// log of recent deliveries, and a function that refreshes them from cfg.
+func newWebhookSettings(cfg *config.Config, saver *config.Saver) (fyne.CanvasObject, func()) {
+	list := container.NewVBox()
+
+	var eventLabels []string
+	for _, event := range config.WebhookEvents {
+		eventLabels = append(eventLabels, strings.TrimRight(i18n.T("event_"+event), ":："))
+	}
+
+	var rebuild func()
+	rebuild = func() {
+		list.RemoveAll()
+		for i := range cfg.Webhooks {
+			i := i
+			hook := &cfg.Webhooks[i]
+
+			urlEntry := widget.NewEntry()
+			urlEntry.SetPlaceHolder("https://example.com/hook")
+			urlEntry.SetText(hook.URL)
+			urlEntry.Validator = config.CheckURL
+			urlEntry.OnChanged = func(s string) {
+				if config.CheckURL(s) == nil {
+					hook.URL = strings.TrimSpace(s)
+					saver.Request()
+				}
+			}
+
+			var selected []string
+			for j, event := range config.WebhookEvents {
+				if slices.Contains(hook.Events, event) {
+					selected = append(selected, eventLabels[j])
+				}
+			}
+			events := widget.NewCheckGroup(eventLabels, nil)
+			events.Horizontal = true
+			events.Selected = selected
+			events.OnChanged = func(labels []string) {
+				var chosen []string
+				for j, label := range eventLabels {
+					if slices.Contains(labels, label) {
+						chosen = append(chosen, config.WebhookEvents[j])
+					}
+				}
+				hook.Events = chosen
+				saver.Request()
+			}
+
+			headersEntry := widget.NewMultiLineEntry()
+			headersEntry.SetPlaceHolder(i18n.T("webhook_headers"))
+			headersEntry.SetText(hook.HeaderText())
+			headersEntry.SetMinRowsVisible(2)
+			headersEntry.Validator = func(s string) error {
+				var w config.Webhook
+				return w.SetHeaderText(s)
+			}
+			headersEntry.OnChanged = func(s string) {
+				var w config.Webhook
+				if w.SetHeaderText(s) == nil {
+					hook.Headers = w.Headers
+					saver.Request()
+				}
+			}
+
+			bodyEntry := widget.NewMultiLineEntry()
+			bodyEntry.SetPlaceHolder(i18n.T("webhook_body"))
+			bodyEntry.SetText(hook.Body)
+			bodyEntry.SetMinRowsVisible(2)
+			bodyEntry.OnChanged = func(s string) {
+				hook.Body = s
+				saver.Request()
+			}
+
+			removeButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				cfg.Webhooks = slices.Delete(cfg.Webhooks, i, i+1)
+				saver.Request()
+				rebuild()
+			})
+
+			list.Add(container.NewBorder(nil, nil, nil, removeButton, urlEntry))
+			list.Add(events)
+			list.Add(container.NewGridWithColumns(2, headersEntry, bodyEntry))
+		}
+	}
+	rebuild()
+
+	addButton := widget.NewButtonWithIcon(i18n.T("webhook_add"), theme.ContentAddIcon(), func() {
+		cfg.Webhooks = append(cfg.Webhooks, config.Webhook{URL: "http://localhost:8080/"})
+		saver.Request()
+		rebuild()
+	})
+
+	hint := widget.NewLabel(fmt.Sprintf(i18n.T("webhook_hint"), strings.Join(config.WebhookPlaceholders, " ")))
+	hint.Wrapping = fyne.TextWrapWord
+
+	deliveries := widget.NewLabel("")
+	deliveries.Wrapping = fyne.TextWrapWord
+	showDeliveries := func() {
+		var lines []string
+		for _, d := range webhooks.Deliveries() {
+			lines = append(lines, d.String())
+		}
+		if len(lines) == 0 {
+			lines = append(lines, i18n.T("webhook_no_deliveries"))
+		}
+		deliveries.SetText(strings.Join(lines, "\n"))
+	}
+	showDeliveries()
+	refreshButton := widget.NewButtonWithIcon(i18n.T("webhook_deliveries"), theme.ViewRefreshIcon(), showDeliveries)
+
+	return container.NewVBox(hint, list, addButton, refreshButton, deliveries), func() {
+		rebuild()
+		showDeliveries()
+	}
+}
@@ -0,0 +1,22 @@
+package gui
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-do-ben/config"
+	"pomodoro-do-ben/pomo"
+)
+
+func TestWebhookPayload(t *testing.T) {
+	cfg := &config.Config{FocusDuration: 25 * time.Minute, ShortBreakDuration: 5 * time.Minute, LongBreakDuration: 15 * time.Minute, LongBreakInterval: 4, CurrentTask: "Report"}
+	timer := pomo.NewTimer(cfg)
+
+	p := webhookPayload(cfg, timer, config.EventPause)
+	if p.Event != config.EventPause || p.Phase != config.PhaseFocus || p.Next != config.PhaseShortBreak || p.Cycle != "1/4" || p.Task != "Report" || p.Remaining != 1500 {
+		t.Errorf("Unexpected payload %+v", p)
+	}
+	if p.PhaseEndsAt != nil {
+		t.Errorf("Expected no end time while stopped, got %v", p.PhaseEndsAt)
+	}
+}
@@ -15,8 +15,6 @@ var messages = map[string]Translations{
 		"focus":                  "Focus",
 		"break":                  "Break",
 		"pomodoro":               "Pomodoro",
-		"time_to_focus":          "It's time to focus!",
-		"time_to_break":          "It's time for a break!",
 		"bens_pomodoro":          "Ben's Pomodoro",
 		"simple_pomodoro":        "A simple pomodoro for Ben",
 		"settings":               "Settings",
@@ -36,6 +34,122 @@ var messages = map[string]Translations{
 		"animation":              "Animation",
 		"icons":                  "Icons",
 		"slideshow":              "Slideshow",
+		"save_failed":            "Could not save settings:",
+		"export_settings":        "Export settings",
+		"import_settings":        "Import settings",
+		"settings_exported":      "Settings exported.",
+		"import_no_changes":      "These settings are already in use.",
+		"import_preview":         "%d setting(s) will change:",
+		"apply":                  "Apply",
+		"cancel":                 "Cancel",
+		"volume":                 "Volume (%)",
+		"master_volume":          "Master:",
+		"cue_volume":             "Cues:",
+		"ambience_volume":        "Ambience:",
+		"meditation_volume":      "Meditation:",
+		"meditation":             "Meditation (binaural beats):",
+		"meditation_minutes":     "🧘 Meditation (%d min)",
+		"meditation_carrier":     "Carrier (Hz):",
+		"meditation_beat":        "Beat:",
+		"meditation_duration":    "Duration (minutes):",
+		"band_alpha":             "Alpha (10 Hz, calm focus)",
+		"band_theta":             "Theta (6 Hz, meditation)",
+		"band_delta":             "Delta (2 Hz, deep relaxation)",
+		"focus_noise":            "Background noise during focus:",
+		"focus_noise_in_breaks":  "Keep the noise playing quietly during breaks",
+		"noise_off":              "Off",
+		"noise_white":            "White noise",
+		"noise_pink":             "Pink noise",
+		"noise_brown":            "Brown noise",
+		"noise_rain":             "Rain",
+		"sounds":                 "Sounds:",
+		"sound_none":             "None",
+		"sound_focus":            "Built-in: focus bell",
+		"sound_break":            "Built-in: break chime",
+		"event_focus_start":      "Focus starts:",
+		"event_break_start":      "Break starts:",
+		"event_long_break_start": "Long break starts:",
+		"event_pause":            "Paused:",
+		"event_warning":          "Phase ending soon:",
+		"event_session_complete": "Cycle complete:",
+		"playlist":               "Ambient playlist (during focus):",
+		"playlist_folder":        "Folder:",
+		"playlist_no_folder":     "No folder chosen",
+		"playlist_crossfade":     "Crossfade (seconds):",
+		"playlist_shuffle":       "Shuffle",
+		"playlist_loop":          "Start over after the last track",
+		"tick_enabled":           "Ticking clock during focus",
+		"tick_style":             "Sound:",
+		"tick_single":            "Tick",
+		"tick_tock":              "Tick-tock",
+		"tick_volume":            "Volume:",
+		"tick_last_minute_only":  "Only in the last minute",
+		"speech":                 "Spoken announcements:",
+		"speech_enabled":         "Announce phase changes aloud instead of playing the sound",
+		"speech_rate":            "Rate (words per minute):",
+		"speech_voice":           "Voice:",
+		"speech_test":            "Test",
+		"speech_engine":          "Using %s.",
+		"speech_no_engine":       "No speech engine found; install espeak-ng. The sound plays instead.",
+		"announce_focus":         "Break over. %d minutes of focus.",
+		"announce_break":         "Focus complete. %d minute break.",
+		"announce_long_break":    "Focus complete. %d minute long break.",
+		"announce_session_complete": "Cycle complete. Next, %d minutes of focus.",
+		"bells":                  "Bells during a phase:",
+		"bell_add":               "Add bell",
+		"bell_when":              "When: 50%, 10m or -1m",
+		"bell_every":             "Repeat every, e.g. 10m",
+		"bell_notify":            "Also show a notification",
+		"phase_focus":            "Focus",
+		"phase_short_break":      "Short break",
+		"phase_long_break":       "Long break",
+		"action_start_break":     "Start break",
+		"action_start_focus":     "Start focus",
+		"action_skip":            "Skip",
+		"action_extend":          "+5 min",
+		"notifiers":              "Notifications:",
+		"notifier_auto":          "Automatic",
+		"notifier_dbus":          "Desktop (D-Bus, with buttons)",
+		"notifier_notify-send":   "notify-send command",
+		"notifier_fyne":          "Built-in (Fyne)",
+		"notifier_stdout":        "Print to the terminal",
+		"notifier_none":          "Off",
+		"notifier_using":         "Using: %s",
+		"notifier_test":          "Test",
+		"notifier_test_message":  "Notifications are working.",
+		"notifier_failed":        "Could not send: %v",
+		"countdown_notification": "Keep a countdown notification while the timer runs",
+		"notify_title":           "🍅 {phase}",
+		"notify_focus_start":     "Time to focus! Pomodoro {cycle}.",
+		"notify_break_start":     "Time for a short break.",
+		"notify_long_break_start": "Cycle done! Time for a long break.",
+		"notify_session_complete": "Break over. Time to focus!",
+		"notify_pause":           "Timer paused with {remaining} left.",
+		"notify_reset":           "Timer reset.",
+		"notify_inactive":        "The timer is inactive during this period.",
+		"notify_warning":         "{remaining} left. {next} is next.",
+		"notify_bell":            "🔔 Take a mindful breath.",
+		"notify_countdown":       "{remaining} left",
+		"event_reset":            "Reset:",
+		"event_inactive":         "Inactive period:",
+		"event_bell":             "Bell:",
+		"event_countdown":        "Countdown:",
+		"messages":               "Notification messages",
+		"messages_hint":          "Leave empty for the default. Placeholders: %s",
+		"task_placeholder":       "What are you working on?",
+		"warnings":               "Warnings before a phase ends",
+		"warning_before":         "Time left, e.g. 2m",
+		"warning_sound":          "Sound",
+		"warning_notify":         "Notification",
+		"warning_add":            "Add warning",
+		"webhooks":               "Webhooks",
+		"webhook_hint":           "Each URL gets a POST on the chosen events (none chosen means all). Without a body template the JSON payload is sent. Placeholders: %s",
+		"webhook_headers":        "Headers, one \"Name: value\" per line",
+		"webhook_body":           "Body template (empty for the JSON payload)",
+		"webhook_add":            "Add webhook",
+		"webhook_deliveries":     "Recent deliveries",
+		"webhook_no_deliveries":  "Nothing delivered yet.",
+		"meditation_fade":        "Fade (seconds):",
 	},
 	"es": {
 		"start":                  "Iniciar",
@@ -44,8 +158,6 @@ var messages = map[string]Translations{
 		"focus":                  "Foco",
 		"break":                  "Pausa",
 		"pomodoro":               "Pomodoro",
-		"time_to_focus":          "¡Es hora de concentrarse!",
-		"time_to_break":          "¡Es hora de un descanso!",
 		"bens_pomodoro":          "Pomodoro de Ben",
 		"simple_pomodoro":        "Un simple pomodoro para Ben",
 		"settings":               "Configuraciones",
@@ -65,6 +177,122 @@ var messages = map[string]Translations{
 		"animation":              "Animación",
 		"icons":                  "Iconos",
 		"slideshow":              "Diapositivas",
+		"save_failed":            "No se pudo guardar la configuración:",
+		"export_settings":        "Exportar configuración",
+		"import_settings":        "Importar configuración",
+		"settings_exported":      "Configuración exportada.",
+		"import_no_changes":      "Esta configuración ya está en uso.",
+		"import_preview":         "Cambiarán %d ajuste(s):",
+		"apply":                  "Aplicar",
+		"cancel":                 "Cancelar",
+		"volume":                 "Volumen (%)",
+		"master_volume":          "General:",
+		"cue_volume":             "Avisos:",
+		"ambience_volume":        "Ambiente:",
+		"meditation_volume":      "Meditación:",
+		"meditation":             "Meditación (pulsos binaurales):",
+		"meditation_minutes":     "🧘 Meditación (%d min)",
+		"meditation_carrier":     "Portadora (Hz):",
+		"meditation_beat":        "Pulso:",
+		"meditation_duration":    "Duración (minutos):",
+		"band_alpha":             "Alfa (10 Hz, foco tranquilo)",
+		"band_theta":             "Theta (6 Hz, meditación)",
+		"band_delta":             "Delta (2 Hz, relajación profunda)",
+		"focus_noise":            "Ruido de fondo durante el enfoque:",
+		"focus_noise_in_breaks":  "Mantener el ruido suave durante los descansos",
+		"noise_off":              "Desactivado",
+		"noise_white":            "Ruido blanco",
+		"noise_pink":             "Ruido rosa",
+		"noise_brown":            "Ruido marrón",
+		"noise_rain":             "Lluvia",
+		"sounds":                 "Sonidos:",
+		"sound_none":             "Ninguno",
+		"sound_focus":            "Incluido: campana de enfoque",
+		"sound_break":            "Incluido: sonido de descanso",
+		"event_focus_start":      "Inicio del enfoque:",
+		"event_break_start":      "Inicio del descanso:",
+		"event_long_break_start": "Inicio del descanso largo:",
+		"event_pause":            "En pausa:",
+		"event_warning":          "Fase a punto de terminar:",
+		"event_session_complete": "Ciclo completo:",
+		"playlist":               "Lista de ambiente (durante el enfoque):",
+		"playlist_folder":        "Carpeta:",
+		"playlist_no_folder":     "Ninguna carpeta elegida",
+		"playlist_crossfade":     "Fundido (segundos):",
+		"playlist_shuffle":       "Aleatorio",
+		"playlist_loop":          "Volver a empezar tras la última pista",
+		"tick_enabled":           "Tictac del reloj durante el enfoque",
+		"tick_style":             "Sonido:",
+		"tick_single":            "Tic",
+		"tick_tock":              "Tictac",
+		"tick_volume":            "Volumen:",
+		"tick_last_minute_only":  "Solo en el último minuto",
+		"speech":                 "Anuncios hablados:",
+		"speech_enabled":         "Anunciar los cambios de fase en voz alta en lugar del sonido",
+		"speech_rate":            "Velocidad (palabras por minuto):",
+		"speech_voice":           "Voz:",
+		"speech_test":            "Probar",
+		"speech_engine":          "Usando %s.",
+		"speech_no_engine":       "No se encontró ningún motor de voz; instala espeak-ng. Se reproducirá el sonido.",
+		"announce_focus":         "Fin del descanso. %d minutos de enfoque.",
+		"announce_break":         "Enfoque completado. Descanso de %d minutos.",
+		"announce_long_break":    "Enfoque completado. Descanso largo de %d minutos.",
+		"announce_session_complete": "Ciclo completado. Ahora, %d minutos de enfoque.",
+		"bells":                  "Campanas durante una fase:",
+		"bell_add":               "Añadir campana",
+		"bell_when":              "Cuándo: 50%, 10m o -1m",
+		"bell_every":             "Repetir cada, p. ej. 10m",
+		"bell_notify":            "Mostrar también una notificación",
+		"phase_focus":            "Foco",
+		"phase_short_break":      "Descanso corto",
+		"phase_long_break":       "Descanso largo",
+		"action_start_break":     "Empezar descanso",
+		"action_start_focus":     "Empezar foco",
+		"action_skip":            "Saltar",
+		"action_extend":          "+5 min",
+		"notifiers":              "Notificaciones:",
+		"notifier_auto":          "Automático",
+		"notifier_dbus":          "Escritorio (D-Bus, con botones)",
+		"notifier_notify-send":   "Comando notify-send",
+		"notifier_fyne":          "Integrado (Fyne)",
+		"notifier_stdout":        "Imprimir en la terminal",
+		"notifier_none":          "Desactivadas",
+		"notifier_using":         "Usando: %s",
+		"notifier_test":          "Probar",
+		"notifier_test_message":  "Las notificaciones funcionan.",
+		"notifier_failed":        "No se pudo enviar: %v",
+		"countdown_notification": "Mantener una notificación con la cuenta atrás mientras corre el temporizador",
+		"notify_title":           "🍅 {phase}",
+		"notify_focus_start":     "¡Es hora de concentrarse! Pomodoro {cycle}.",
+		"notify_break_start":     "Es hora de un descanso corto.",
+		"notify_long_break_start": "¡Ciclo completo! Es hora de un descanso largo.",
+		"notify_session_complete": "Se acabó el descanso. ¡A concentrarse!",
+		"notify_pause":           "Temporizador en pausa, quedan {remaining}.",
+		"notify_reset":           "Temporizador reiniciado.",
+		"notify_inactive":        "El temporizador está inactivo en este periodo.",
+		"notify_warning":         "Quedan {remaining}. Después: {next}.",
+		"notify_bell":            "🔔 Respira con atención.",
+		"notify_countdown":       "Quedan {remaining}",
+		"event_reset":            "Reinicio:",
+		"event_inactive":         "Periodo inactivo:",
+		"event_bell":             "Campana:",
+		"event_countdown":        "Cuenta atrás:",
+		"messages":               "Mensajes de notificación",
+		"messages_hint":          "Déjalo vacío para usar el predeterminado. Marcadores: %s",
+		"task_placeholder":       "¿En qué estás trabajando?",
+		"warnings":               "Avisos antes de que termine una fase",
+		"warning_before":         "Tiempo restante, p. ej. 2m",
+		"warning_sound":          "Sonido",
+		"warning_notify":         "Notificación",
+		"warning_add":            "Añadir aviso",
+		"webhooks":               "Webhooks",
+		"webhook_hint":           "Cada URL recibe un POST en los eventos elegidos (ninguno significa todos). Sin plantilla de cuerpo se envía el JSON. Marcadores: %s",
+		"webhook_headers":        "Cabeceras, una \"Nombre: valor\" por línea",
+		"webhook_body":           "Plantilla del cuerpo (vacía para el JSON)",
+		"webhook_add":            "Añadir webhook",
+		"webhook_deliveries":     "Entregas recientes",
+		"webhook_no_deliveries":  "Nada entregado todavía.",
+		"meditation_fade":        "Fundido (segundos):",
 	},
 	"zh": {
 		"start":                  "开始",
@@ -73,8 +301,6 @@ var messages = map[string]Translations{
 		"focus":                  "专注",
 		"break":                  "休息",
 		"pomodoro":               "番茄钟",
-		"time_to_focus":          "是时候专注了！",
-		"time_to_break":          "是时候休息一下了！",
 		"bens_pomodoro":          "Ben的番茄钟",
 		"simple_pomodoro":        "一个简单的番茄钟",
 		"settings":               "设置",
@@ -94,6 +320,122 @@ var messages = map[string]Translations{
 		"animation":              "动画",
 		"icons":                  "图标",
 		"slideshow":              "幻灯片",
+		"save_failed":            "无法保存设置：",
+		"export_settings":        "导出设置",
+		"import_settings":        "导入设置",
+		"settings_exported":      "设置已导出。",
+		"import_no_changes":      "这些设置已在使用中。",
+		"import_preview":         "将更改 %d 项设置：",
+		"apply":                  "应用",
+		"cancel":                 "取消",
+		"volume":                 "音量（%）",
+		"master_volume":          "总音量：",
+		"cue_volume":             "提示音：",
+		"ambience_volume":        "环境音：",
+		"meditation_volume":      "冥想：",
+		"meditation":             "冥想（双耳节拍）：",
+		"meditation_minutes":     "🧘 冥想（%d 分钟）",
+		"meditation_carrier":     "载波（Hz）：",
+		"meditation_beat":        "节拍：",
+		"meditation_duration":    "时长（分钟）：",
+		"band_alpha":             "Alpha（10 Hz，平静专注）",
+		"band_theta":             "Theta（6 Hz，冥想）",
+		"band_delta":             "Delta（2 Hz，深度放松）",
+		"focus_noise":            "专注时的背景噪音：",
+		"focus_noise_in_breaks":  "休息时以低音量继续播放噪音",
+		"noise_off":              "关闭",
+		"noise_white":            "白噪音",
+		"noise_pink":             "粉红噪音",
+		"noise_brown":            "棕色噪音",
+		"noise_rain":             "雨声",
+		"sounds":                 "声音：",
+		"sound_none":             "无",
+		"sound_focus":            "内置：专注铃声",
+		"sound_break":            "内置：休息提示音",
+		"event_focus_start":      "开始专注：",
+		"event_break_start":      "开始休息：",
+		"event_long_break_start": "开始长休息：",
+		"event_pause":            "暂停：",
+		"event_warning":          "阶段即将结束：",
+		"event_session_complete": "完成一轮：",
+		"playlist":               "环境播放列表（专注时）：",
+		"playlist_folder":        "文件夹：",
+		"playlist_no_folder":     "未选择文件夹",
+		"playlist_crossfade":     "交叉淡入淡出（秒）：",
+		"playlist_shuffle":       "随机播放",
+		"playlist_loop":          "播完后从头开始",
+		"tick_enabled":           "专注时播放时钟滴答声",
+		"tick_style":             "声音：",
+		"tick_single":            "滴",
+		"tick_tock":              "滴答",
+		"tick_volume":            "音量：",
+		"tick_last_minute_only":  "仅在最后一分钟",
+		"speech":                 "语音播报：",
+		"speech_enabled":         "用语音播报阶段变化，代替提示音",
+		"speech_rate":            "语速（每分钟字数）：",
+		"speech_voice":           "嗓音：",
+		"speech_test":            "试听",
+		"speech_engine":          "正在使用 %s。",
+		"speech_no_engine":       "未找到语音引擎，请安装 espeak-ng。将改为播放提示音。",
+		"announce_focus":         "休息结束。专注 %d 分钟。",
+		"announce_break":         "专注完成。休息 %d 分钟。",
+		"announce_long_break":    "专注完成。长休息 %d 分钟。",
+		"announce_session_complete": "一轮完成。接下来专注 %d 分钟。",
+		"bells":                  "阶段中的铃声：",
+		"bell_add":               "添加铃声",
+		"bell_when":              "时间：50%、10m 或 -1m",
+		"bell_every":             "重复间隔，如 10m",
+		"bell_notify":            "同时显示通知",
+		"phase_focus":            "专注",
+		"phase_short_break":      "短休息",
+		"phase_long_break":       "长休息",
+		"action_start_break":     "开始休息",
+		"action_start_focus":     "开始专注",
+		"action_skip":            "跳过",
+		"action_extend":          "+5 分钟",
+		"notifiers":              "通知：",
+		"notifier_auto":          "自动",
+		"notifier_dbus":          "桌面（D-Bus，带按钮）",
+		"notifier_notify-send":   "notify-send 命令",
+		"notifier_fyne":          "内置（Fyne）",
+		"notifier_stdout":        "输出到终端",
+		"notifier_none":          "关闭",
+		"notifier_using":         "正在使用：%s",
+		"notifier_test":          "测试",
+		"notifier_test_message":  "通知工作正常。",
+		"notifier_failed":        "无法发送：%v",
+		"countdown_notification": "计时时保留倒计时通知",
+		"notify_title":           "🍅 {phase}",
+		"notify_focus_start":     "该专注了！番茄钟 {cycle}。",
+		"notify_break_start":     "该短暂休息了。",
+		"notify_long_break_start": "一轮完成！该长时间休息了。",
+		"notify_session_complete": "休息结束，该专注了！",
+		"notify_pause":           "计时器已暂停，剩余 {remaining}。",
+		"notify_reset":           "计时器已重置。",
+		"notify_inactive":        "此时段计时器处于非活动状态。",
+		"notify_warning":         "剩余 {remaining}。接下来：{next}。",
+		"notify_bell":            "🔔 深呼吸，保持觉察。",
+		"notify_countdown":       "剩余 {remaining}",
+		"event_reset":            "重置：",
+		"event_inactive":         "非活动时段：",
+		"event_bell":             "铃声：",
+		"event_countdown":        "倒计时：",
+		"messages":               "通知消息",
+		"messages_hint":          "留空则使用默认值。占位符：%s",
+		"task_placeholder":       "你在做什么？",
+		"warnings":               "阶段结束前提醒",
+		"warning_before":         "剩余时间，例如 2m",
+		"warning_sound":          "声音",
+		"warning_notify":         "通知",
+		"warning_add":            "添加提醒",
+		"webhooks":               "Webhook",
+		"webhook_hint":           "每个 URL 会在所选事件时收到 POST（不选则为全部）。没有正文模板时发送 JSON 数据。占位符：%s",
+		"webhook_headers":        "请求头，每行一个 \"名称: 值\"",
+		"webhook_body":           "正文模板（留空发送 JSON）",
+		"webhook_add":            "添加 Webhook",
+		"webhook_deliveries":     "最近的投递",
+		"webhook_no_deliveries":  "尚未投递。",
+		"meditation_fade":        "淡入淡出（秒）：",
 	},
 	"pt": {
 		"start":                  "Iniciar",
@@ -102,8 +444,6 @@ var messages = map[string]Translations{
 		"focus":                  "Foco",
 		"break":                  "Pausa",
 		"pomodoro":               "Pomodoro",
-		"time_to_focus":          "É hora de focar!",
-		"time_to_break":          "É hora de uma pausa!",
 		"bens_pomodoro":          "Pomodoro do Ben",
 		"simple_pomodoro":        "Um simples pomodoro para o Ben",
 		"settings":               "Configurações",
@@ -123,6 +463,122 @@ var messages = map[string]Translations{
 		"animation":              "Animação",
 		"icons":                  "Ícones",
 		"slideshow":              "Apresentação de slides",
+		"save_failed":            "Não foi possível salvar as configurações:",
+		"export_settings":        "Exportar configurações",
+		"import_settings":        "Importar configurações",
+		"settings_exported":      "Configurações exportadas.",
+		"import_no_changes":      "Essas configurações já estão em uso.",
+		"import_preview":         "%d configuração(ões) serão alteradas:",
+		"apply":                  "Aplicar",
+		"cancel":                 "Cancelar",
+		"volume":                 "Volume (%)",
+		"master_volume":          "Geral:",
+		"cue_volume":             "Avisos:",
+		"ambience_volume":        "Ambiente:",
+		"meditation_volume":      "Meditação:",
+		"meditation":             "Meditação (batidas binaurais):",
+		"meditation_minutes":     "🧘 Meditação (%d min)",
+		"meditation_carrier":     "Portadora (Hz):",
+		"meditation_beat":        "Batida:",
+		"meditation_duration":    "Duração (minutos):",
+		"band_alpha":             "Alfa (10 Hz, foco calmo)",
+		"band_theta":             "Teta (6 Hz, meditação)",
+		"band_delta":             "Delta (2 Hz, relaxamento profundo)",
+		"focus_noise":            "Ruído de fundo durante o foco:",
+		"focus_noise_in_breaks":  "Manter o ruído baixinho durante as pausas",
+		"noise_off":              "Desligado",
+		"noise_white":            "Ruído branco",
+		"noise_pink":             "Ruído rosa",
+		"noise_brown":            "Ruído marrom",
+		"noise_rain":             "Chuva",
+		"sounds":                 "Sons:",
+		"sound_none":             "Nenhum",
+		"sound_focus":            "Embutido: sino de foco",
+		"sound_break":            "Embutido: som de pausa",
+		"event_focus_start":      "Início do foco:",
+		"event_break_start":      "Início da pausa:",
+		"event_long_break_start": "Início da pausa longa:",
+		"event_pause":            "Pausado:",
+		"event_warning":          "Fase quase no fim:",
+		"event_session_complete": "Ciclo completo:",
+		"playlist":               "Playlist ambiente (durante o foco):",
+		"playlist_folder":        "Pasta:",
+		"playlist_no_folder":     "Nenhuma pasta escolhida",
+		"playlist_crossfade":     "Transição (segundos):",
+		"playlist_shuffle":       "Aleatório",
+		"playlist_loop":          "Recomeçar após a última faixa",
+		"tick_enabled":           "Tique-taque do relógio durante o foco",
+		"tick_style":             "Som:",
+		"tick_single":            "Tique",
+		"tick_tock":              "Tique-taque",
+		"tick_volume":            "Volume:",
+		"tick_last_minute_only":  "Só no último minuto",
+		"speech":                 "Anúncios falados:",
+		"speech_enabled":         "Anunciar as mudanças de fase em voz alta em vez do som",
+		"speech_rate":            "Velocidade (palavras por minuto):",
+		"speech_voice":           "Voz:",
+		"speech_test":            "Testar",
+		"speech_engine":          "Usando %s.",
+		"speech_no_engine":       "Nenhum mecanismo de voz encontrado; instale o espeak-ng. O som será tocado no lugar.",
+		"announce_focus":         "Fim da pausa. %d minutos de foco.",
+		"announce_break":         "Foco concluído. Pausa de %d minutos.",
+		"announce_long_break":    "Foco concluído. Pausa longa de %d minutos.",
+		"announce_session_complete": "Ciclo concluído. Agora, %d minutos de foco.",
+		"bells":                  "Sinos durante uma fase:",
+		"bell_add":               "Adicionar sino",
+		"bell_when":              "Quando: 50%, 10m ou -1m",
+		"bell_every":             "Repetir a cada, ex. 10m",
+		"bell_notify":            "Mostrar também uma notificação",
+		"phase_focus":            "Foco",
+		"phase_short_break":      "Pausa curta",
+		"phase_long_break":       "Pausa longa",
+		"action_start_break":     "Iniciar pausa",
+		"action_start_focus":     "Iniciar foco",
+		"action_skip":            "Pular",
+		"action_extend":          "+5 min",
+		"notifiers":              "Notificações:",
+		"notifier_auto":          "Automático",
+		"notifier_dbus":          "Área de trabalho (D-Bus, com botões)",
+		"notifier_notify-send":   "Comando notify-send",
+		"notifier_fyne":          "Embutido (Fyne)",
+		"notifier_stdout":        "Imprimir no terminal",
+		"notifier_none":          "Desligadas",
+		"notifier_using":         "Usando: %s",
+		"notifier_test":          "Testar",
+		"notifier_test_message":  "As notificações estão funcionando.",
+		"notifier_failed":        "Não foi possível enviar: %v",
+		"countdown_notification": "Manter uma notificação de contagem regressiva enquanto o timer roda",
+		"notify_title":           "🍅 {phase}",
+		"notify_focus_start":     "Hora de focar! Pomodoro {cycle}.",
+		"notify_break_start":     "Hora de uma pausa curta.",
+		"notify_long_break_start": "Ciclo concluído! Hora de uma pausa longa.",
+		"notify_session_complete": "Fim da pausa. Hora de focar!",
+		"notify_pause":           "Timer pausado com {remaining} restantes.",
+		"notify_reset":           "Timer reiniciado.",
+		"notify_inactive":        "O timer está inativo neste período.",
+		"notify_warning":         "Faltam {remaining}. Depois: {next}.",
+		"notify_bell":            "🔔 Respire com atenção.",
+		"notify_countdown":       "Faltam {remaining}",
+		"event_reset":            "Reinício:",
+		"event_inactive":         "Período inativo:",
+		"event_bell":             "Sino:",
+		"event_countdown":        "Contagem regressiva:",
+		"messages":               "Mensagens de notificação",
+		"messages_hint":          "Deixe vazio para usar o padrão. Marcadores: %s",
+		"task_placeholder":       "No que você está trabalhando?",
+		"warnings":               "Avisos antes do fim de uma fase",
+		"warning_before":         "Tempo restante, ex. 2m",
+		"warning_sound":          "Som",
+		"warning_notify":         "Notificação",
+		"warning_add":            "Adicionar aviso",
+		"webhooks":               "Webhooks",
+		"webhook_hint":           "Cada URL recebe um POST nos eventos escolhidos (nenhum significa todos). Sem modelo de corpo, o JSON é enviado. Marcadores: %s",
+		"webhook_headers":        "Cabeçalhos, um \"Nome: valor\" por linha",
+		"webhook_body":           "Modelo do corpo (vazio para o JSON)",
+		"webhook_add":            "Adicionar webhook",
+		"webhook_deliveries":     "Entregas recentes",
+		"webhook_no_deliveries":  "Nada entregue ainda.",
+		"meditation_fade":        "Fade (segundos):",
 	},
 }
 
@@ -151,3 +607,11 @@ func T(key string) string {
 	}
 	return key // return key if no translation is found
 }
+
+// Lang returns the language code in use, e.g. "en".
+func Lang() string {
+	if _, ok := messages[lang]; ok {
+		return lang
+	}
+	return "en"
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -12,11 +15,26 @@ import (
 )
 
 func main() {
-	cfg, err := config.Load()
+	opts, err := config.ParseOptions(os.Args[1:], os.Environ(), os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadWithOptions(opts)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if handled, err := runCommand(cfg, opts, os.Stdin, os.Stdout); handled {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	myApp := app.New()
 	myWindow := myApp.NewWindow(i18n.T("bens_pomodoro"))
 
@@ -0,0 +1,243 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// Names of the notifiers Open knows.
+const (
+	Auto           = "auto" // D-Bus, else notify-send, else Fyne
+	DBusName       = "dbus"
+	NotifySendName = "notify-send"
+	FyneName       = "fyne"
+	StdoutName     = "stdout"
+	NoneName       = "none"
+)
+
+// Names lists what Open accepts, in the order shown in Settings.
+var Names = []string{Auto, DBusName, NotifySendName, FyneName, StdoutName, NoneName}
+
+// ErrUnavailable is returned by notifiers that cannot work on this system.
+var ErrUnavailable = errors.New("notifier unavailable")
+
+// Open returns the notifier called name, falling back to the automatic
+// choice when it cannot be used here. app is used by the Fyne notifier and
+// may be nil.
+func Open(name string, app fyne.App) Notifier {
+	var chain Fallback
+	add := func(n Notifier, err error) {
+		if err != nil {
+			log.Println("Notifications:", err)
+			return
+		}
+		chain = append(chain, n)
+	}
+	open := func(name string) {
+		switch name {
+		case DBusName:
+			add(NewDBus())
+		case NotifySendName:
+			add(NewNotifySend())
+		case FyneName:
+			add(NewFyne(app))
+		}
+	}
+
+	switch name {
+	case NoneName:
+		return Null{}
+	case StdoutName:
+		// Printing cannot fail, so there is nothing to fall back to.
+		return NewWriter(os.Stdout)
+	case Auto, "":
+	default:
+		open(name)
+	}
+	for _, fallback := range []string{DBusName, NotifySendName, FyneName} {
+		if fallback != name {
+			open(fallback)
+		}
+	}
+	if len(chain) == 0 {
+		return Null{}
+	}
+	return chain
+}
+
+// Fallback sends through the first of its notifiers that succeeds.
+type Fallback []Notifier
+
+func (f Fallback) Name() string {
+	var names []string
+	for _, n := range f {
+		names = append(names, n.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+func (f Fallback) Send(n Notification) error {
+	var errs []error
+	for _, notifier := range f {
+		err := notifier.Send(n)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the notifiers that hold a connection.
+func (f Fallback) Close() error {
+	var errs []error
+	for _, n := range f {
+		if c, ok := n.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifySend runs the notify-send command. It has no buttons.
+type NotifySend struct {
+	path string
+}
+
+// NewNotifySend fails if notify-send is not installed.
+func NewNotifySend() (*NotifySend, error) {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return &NotifySend{path: path}, nil
+}
+
+func (ns *NotifySend) Name() string { return NotifySendName }
+
+func (ns *NotifySend) Send(n Notification) error {
+	args := []string{"-a", appName, "-i", n.icon(), "-u", n.Urgency.String()}
+	if n.Category != "" {
+		args = append(args, "-c", n.Category)
+	}
+	if t := n.expireTimeout(); t >= 0 {
+		args = append(args, "-t", strconv.Itoa(int(t)))
+	}
+	output, err := exec.Command(ns.path, append(args, n.Title, n.Body)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Fyne shows notifications through the Fyne app, which works on every
+// platform Fyne supports but has no buttons or hints.
+type Fyne struct {
+	app fyne.App
+}
+
+// NewFyne fails if app is nil.
+func NewFyne(app fyne.App) (*Fyne, error) {
+	if app == nil {
+		return nil, fmt.Errorf("%w: no Fyne app", ErrUnavailable)
+	}
+	return &Fyne{app: app}, nil
+}
+
+func (f *Fyne) Name() string { return FyneName }
+
+func (f *Fyne) Send(n Notification) error {
+	f.app.SendNotification(fyne.NewNotification(n.Title, n.Body))
+	return nil
+}
+
+// Writer prints notifications as lines of text, e.g. to a terminal.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Name() string { return StdoutName }
+
+func (w *Writer) Send(n Notification) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.w, "notification: %s: %s\n", n.Title, n.Body)
+	return err
+}
+
+// Null drops every notification.
+type Null struct{}
+
+func (Null) Name() string              { return NoneName }
+func (Null) Send(n Notification) error { return nil }
+
+// Recorder keeps every notification it is sent, for tests. Updates are
+// recorded like new notifications.
+type Recorder struct {
+	mu      sync.Mutex
+	sent    []Notification
+	ids     []uint32
+	removed []uint32
+	lastID  uint32
+}
+
+func (r *Recorder) Name() string { return "recorder" }
+
+func (r *Recorder) Send(n Notification) error {
+	_, err := r.Update(0, n)
+	return err
+}
+
+func (r *Recorder) Update(id uint32, n Notification) (uint32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id == 0 {
+		r.lastID++
+		id = r.lastID
+	}
+	r.sent = append(r.sent, n)
+	r.ids = append(r.ids, id)
+	return id, nil
+}
+
+func (r *Recorder) Remove(id uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed = append(r.removed, id)
+	return nil
+}
+
+// IDs returns the ID of each notification returned by Sent.
+func (r *Recorder) IDs() []uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uint32(nil), r.ids...)
+}
+
+// Removed returns the IDs of the notifications removed so far.
+func (r *Recorder) Removed() []uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uint32(nil), r.removed...)
+}
+
+// Sent returns the notifications sent so far, oldest first.
+func (r *Recorder) Sent() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Notification(nil), r.sent...)
+}
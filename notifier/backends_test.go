@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type failing struct{}
+
+func (failing) Name() string              { return "failing" }
+func (failing) Send(n Notification) error { return errors.New("broken") }
+
+func TestFallback(t *testing.T) {
+	rec := &Recorder{}
+	f := Fallback{failing{}, rec}
+	if err := f.Send(Notification{Title: "a"}); err != nil {
+		t.Fatalf("Expected the recorder to take over, got %v", err)
+	}
+	if sent := rec.Sent(); len(sent) != 1 || sent[0].Title != "a" {
+		t.Errorf("Expected one notification recorded, got %+v", sent)
+	}
+	if got := f.Name(); got != "failing, recorder" {
+		t.Errorf("Expected both names in order, got %q", got)
+	}
+	if err := (Fallback{failing{}}).Send(Notification{}); err == nil {
+		t.Error("Expected an error when every notifier fails")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if n := Open(NoneName, nil); n.Name() != NoneName {
+		t.Errorf("Expected the null notifier, got %s", n.Name())
+	}
+	if n := Open(StdoutName, nil); n.Name() != StdoutName {
+		t.Errorf("Expected the stdout notifier, got %s", n.Name())
+	}
+}
+
+func TestDefault(t *testing.T) {
+	rec := &Recorder{}
+	SetDefault(rec)
+	defer SetDefault(nil)
+
+	Notify("Title", "Body")
+	if sent := rec.Sent(); len(sent) != 1 || sent[0].Title != "Title" || sent[0].Body != "Body" {
+		t.Errorf("Expected Notify to go through the default, got %+v", sent)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	NewWriter(&buf).Send(Notification{Title: "Pomodoro", Body: "Break!"})
+	if got := buf.String(); got != "notification: Pomodoro: Break!\n" {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	rec := &Recorder{}
+	SetDefault(Fallback{failing{}, rec})
+	defer SetDefault(nil)
+
+	var c Countdown
+	for _, body := range []string{"25 min", "24 min"} {
+		if err := c.Show(Notification{Body: body}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ids := rec.IDs(); len(ids) != 2 || ids[0] != ids[1] {
+		t.Errorf("Expected the second update to replace the first, got IDs %v", ids)
+	}
+	c.Close()
+	c.Close()
+	if removed := rec.Removed(); len(removed) != 1 || removed[0] != rec.IDs()[0] {
+		t.Errorf("Expected the countdown to be removed once, got %v", removed)
+	}
+
+	c.Show(Notification{Body: "5 min"})
+	if ids := rec.IDs(); ids[2] == ids[0] {
+		t.Error("Expected a new notification after closing")
+	}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The freedesktop.org notification service.
+const (
+	dbusName      = "org.freedesktop.Notifications"
+	dbusPath      = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusInterface = "org.freedesktop.Notifications"
+)
+
+// DBus sends notifications straight to the desktop's notification server and
+// runs the action a button stands for when it is clicked.
+type DBus struct {
+	conn       *dbus.Conn
+	obj        dbus.BusObject
+	hasActions bool
+
+	mu      sync.Mutex
+	actions map[uint32][]Action // notification ID -> its buttons
+}
+
+// NewDBus connects to the session bus. It fails if there is no notification
+// server.
+func NewDBus() (*DBus, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the session bus: %w", err)
+	}
+	d := &DBus{conn: conn, obj: conn.Object(dbusName, dbusPath), actions: make(map[uint32][]Action)}
+
+	var caps []string
+	if err := d.obj.Call(dbusInterface+".GetCapabilities", 0).Store(&caps); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("no notification server: %w", err)
+	}
+	d.hasActions = slices.Contains(caps, "actions")
+
+	if err := conn.AddMatchSignal(dbus.WithMatchObjectPath(dbusPath), dbus.WithMatchInterface(dbusInterface)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("listening for notification signals: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	go d.listen(signals)
+	return d, nil
+}
+
+func (d *DBus) Name() string { return DBusName }
+
+func (d *DBus) Send(n Notification) error {
+	_, err := d.Update(0, n)
+	return err
+}
+
+// Update shows n in place of the notification with the given ID, or as a new
+// one if id is 0 or that notification is gone, and returns the ID the server
+// gave it. Actions are dropped if the server cannot show buttons.
+func (d *DBus) Update(id uint32, n Notification) (uint32, error) {
+	var actions []string
+	if d.hasActions {
+		for i, a := range n.Actions {
+			actions = append(actions, strconv.Itoa(i), a.Label)
+		}
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(n.Urgency.level()),
+		"desktop-entry": dbus.MakeVariant(desktopEntry),
+	}
+	if n.Category != "" {
+		hints["category"] = dbus.MakeVariant(n.Category)
+	}
+
+	call := d.obj.Call(dbusInterface+".Notify", 0, appName, id, n.icon(), n.Title, n.Body, actions, hints, n.expireTimeout())
+	if err := call.Store(&id); err != nil {
+		return 0, fmt.Errorf("sending notification: %w", err)
+	}
+	d.mu.Lock()
+	if len(actions) > 0 {
+		d.actions[id] = n.Actions
+	} else {
+		delete(d.actions, id)
+	}
+	d.mu.Unlock()
+	return id, nil
+}
+
+// Remove closes the notification with the given ID.
+func (d *DBus) Remove(id uint32) error {
+	return d.obj.Call(dbusInterface+".CloseNotification", 0, id).Err
+}
+
+// Close disconnects from the session bus.
+func (d *DBus) Close() error {
+	return d.conn.Close()
+}
+
+// listen runs the actions of clicked buttons and forgets the buttons of
+// notifications that were closed.
+func (d *DBus) listen(signals <-chan *dbus.Signal) {
+	for s := range signals {
+		if len(s.Body) < 2 {
+			continue
+		}
+		id, ok := s.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		switch s.Name {
+		case dbusInterface + ".ActionInvoked":
+			key, _ := s.Body[1].(string)
+			d.mu.Lock()
+			actions := d.actions[id]
+			delete(d.actions, id)
+			d.mu.Unlock()
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(actions) && actions[i].Do != nil {
+				go actions[i].Do()
+			}
+		case dbusInterface + ".NotificationClosed":
+			d.mu.Lock()
+			delete(d.actions, id)
+			d.mu.Unlock()
+		}
+	}
+}
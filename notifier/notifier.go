@@ -1,17 +1,201 @@
-
 package notifier
 
 import (
+	"io"
 	"log"
-	"os/exec"
-	"pomodoro-do-ben/i18n"
+	"sync"
+	"time"
+)
+
+// How the app names itself to the notification server.
+const (
+	appName      = "Pomodoro do Ben"
+	desktopEntry = "pomodoro" // pomodoro.desktop
+	defaultIcon  = "pomodoro-do-ben"
 )
 
+// Urgency is how insistently a notification is shown.
+type Urgency int
+
+const (
+	UrgencyNormal Urgency = iota
+	UrgencyLow
+	UrgencyCritical
+)
+
+// level is the urgency as the notification spec numbers it.
+func (u Urgency) level() byte {
+	switch u {
+	case UrgencyLow:
+		return 0
+	case UrgencyCritical:
+		return 2
+	}
+	return 1
+}
+
+func (u Urgency) String() string {
+	switch u {
+	case UrgencyLow:
+		return "low"
+	case UrgencyCritical:
+		return "critical"
+	}
+	return "normal"
+}
+
+// Persistent is a Timeout that keeps a notification up until it is clicked
+// or closed.
+const Persistent time.Duration = -1
+
+// Notification is a desktop notification with the hints of the
+// freedesktop.org notification spec.
+type Notification struct {
+	Title    string
+	Body     string
+	Icon     string // icon name or file; empty uses the app icon
+	Urgency  Urgency
+	Category string        // e.g. "presence"; see the spec for the list
+	Timeout  time.Duration // 0 lets the server decide
+	Actions  []Action      // buttons; only shown over D-Bus
+}
+
+// Action is a button on a notification. Do runs on its own goroutine when the
+// button is clicked.
+type Action struct {
+	Label string
+	Do    func()
+}
+
+func (n Notification) icon() string {
+	if n.Icon == "" {
+		return defaultIcon
+	}
+	return n.Icon
+}
+
+// expireTimeout is Timeout as the spec wants it: milliseconds, -1 for the
+// server's default and 0 for never.
+func (n Notification) expireTimeout() int32 {
+	switch {
+	case n.Timeout == 0:
+		return -1
+	case n.Timeout < 0:
+		return 0
+	}
+	return int32(n.Timeout.Milliseconds())
+}
+
+// Notifier shows notifications somewhere.
+type Notifier interface {
+	// Name is the name Open knows the notifier by.
+	Name() string
+	Send(n Notification) error
+}
+
+// Updater is implemented by notifiers that can change a notification after
+// showing it.
+type Updater interface {
+	// Update shows n in place of the notification with the given ID, or as a
+	// new one if id is 0, and returns its ID.
+	Update(id uint32, n Notification) (uint32, error)
+	// Remove closes the notification with the given ID.
+	Remove(id uint32) error
+}
+
+var (
+	mu      sync.Mutex
+	current Notifier
+)
+
+// SetDefault makes Notify and Send use n. The notifier used before is closed
+// if it holds a connection.
+func SetDefault(n Notifier) {
+	mu.Lock()
+	old := current
+	current = n
+	mu.Unlock()
+	if c, ok := old.(io.Closer); ok && old != n {
+		c.Close()
+	}
+}
+
+// Default returns the notifier used by Notify and Send, opening the
+// automatic choice the first time.
+func Default() Notifier {
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		current = Open(Auto, nil)
+	}
+	return current
+}
+
+// Notify shows a plain notification. The text is shown as it is.
 func Notify(title, message string) {
-	cmd := exec.Command("notify-send", i18n.T(title), i18n.T(message))
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	if err := Send(Notification{Title: title, Body: message}); err != nil {
 		log.Println("Error sending notification:", err)
-		log.Println("notify-send output:", string(output))
 	}
 }
+
+// Send shows n through the default notifier.
+func Send(n Notification) error {
+	return Default().Send(n)
+}
+
+// Countdown is one notification that is replaced in place each time it is
+// shown, until it is closed. It only appears with a notifier that can update
+// notifications.
+type Countdown struct {
+	mu      sync.Mutex
+	updater Updater
+	id      uint32
+}
+
+// Show shows n, replacing what the countdown showed before.
+func (c *Countdown) Show(n Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u := updater(Default())
+	if u == nil {
+		return nil
+	}
+	if u != c.updater {
+		// The notifier changed; start over with it.
+		c.updater, c.id = u, 0
+	}
+	id, err := u.Update(c.id, n)
+	if err != nil {
+		return err
+	}
+	c.id = id
+	return nil
+}
+
+// Close removes the notification, if it is showing.
+func (c *Countdown) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.id == 0 {
+		return nil
+	}
+	id := c.id
+	c.id = 0
+	return c.updater.Remove(id)
+}
+
+// updater returns n, or the first notifier n falls back to, that can update
+// notifications.
+func updater(n Notifier) Updater {
+	if u, ok := n.(Updater); ok {
+		return u
+	}
+	if f, ok := n.(Fallback); ok {
+		for _, n := range f {
+			if u, ok := n.(Updater); ok {
+				return u
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package player
+
+import (
+	"io"
+	"math"
+	"time"
+)
+
+// Beat frequencies, in Hz, for the brainwave bands binaural beats are
+// commonly used to encourage.
+const (
+	DeltaBeat = 2.0  // deep relaxation, sleep
+	ThetaBeat = 6.0  // meditation
+	AlphaBeat = 10.0 // calm focus
+)
+
+// Binaural describes a binaural beat: a tone of Carrier-Beat/2 Hz in the left
+// ear and Carrier+Beat/2 Hz in the right, which is perceived as a pulse at
+// Beat Hz.
+type Binaural struct {
+	Carrier  float64       // Hz; 100-400 Hz works well
+	Beat     float64       // Hz
+	Duration time.Duration // 0 plays until stopped
+	Fade     time.Duration // fade in at the start, fade out before the end
+}
+
+// binauralAmplitude leaves headroom so the tones stay comfortable at full
+// volume.
+const binauralAmplitude = 0.4
+
+type binauralSource struct {
+	left, right float64 // phase increment per frame, in radians
+	phaseL      float64
+	phaseR      float64
+	frame       int
+	frames      int // total frames, 0 for endless
+	fadeFrames  int
+}
+
+func newBinauralSource(b Binaural) source {
+	step := 2 * math.Pi / SampleRate
+	return &binauralSource{
+		left:       (b.Carrier - b.Beat/2) * step,
+		right:      (b.Carrier + b.Beat/2) * step,
+		frames:     int(b.Duration.Seconds() * SampleRate),
+		fadeFrames: int(b.Fade.Seconds() * SampleRate),
+	}
+}
+
+func (s *binauralSource) Read(samples []float32) (int, error) {
+	n := 0
+	for ; n+1 < len(samples); n += 2 {
+		if s.frames > 0 && s.frame >= s.frames {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			return n, nil
+		}
+		amp := binauralAmplitude * s.envelope()
+		samples[n] = float32(amp * math.Sin(s.phaseL))
+		samples[n+1] = float32(amp * math.Sin(s.phaseR))
+		s.phaseL = math.Mod(s.phaseL+s.left, 2*math.Pi)
+		s.phaseR = math.Mod(s.phaseR+s.right, 2*math.Pi)
+		s.frame++
+	}
+	return n, nil
+}
+
+func (s *binauralSource) length() int64 { return int64(s.frames) }
+
+// envelope fades the tones out over the last fadeFrames of a timed session.
+// The fade-in is done by the player.
+func (s *binauralSource) envelope() float64 {
+	if s.frames == 0 || s.fadeFrames == 0 {
+		return 1
+	}
+	left := s.frames - s.frame
+	if left >= s.fadeFrames {
+		return 1
+	}
+	return float64(left) / float64(s.fadeFrames)
+}
+
+// BinauralPlayer synthesizes binaural beats for the meditation session. Its
+// volume follows the Meditation category.
+type BinauralPlayer struct {
+	track *Track
+}
+
+// NewBinauralPlayer returns a player on the meditation track of the default
+// mixer.
+func NewBinauralPlayer() *BinauralPlayer {
+	return &BinauralPlayer{track: defaultMixer.Track(MeditationTrack)}
+}
+
+// Play starts a binaural session, replacing any session in progress.
+func (bp *BinauralPlayer) Play(b Binaural) error {
+	bp.track.SetFade(b.Fade, b.Fade)
+	return bp.track.playSource("binaural", newBinauralSource(b), nil)
+}
+
+func (bp *BinauralPlayer) Stop() {
+	bp.track.Stop()
+}
+
+// IsPlaying reports whether a session is in progress.
+func (bp *BinauralPlayer) IsPlaying() bool {
+	return bp.track.IsPlaying()
+}
+
+// Position returns how long the current session has been playing.
+func (bp *BinauralPlayer) Position() time.Duration {
+	return bp.track.Position()
+}
+
+// Duration returns the length of the current session, or 0 for an endless
+// one.
+func (bp *BinauralPlayer) Duration() time.Duration {
+	return bp.track.Duration()
+}
+
+// OnFinished registers f to be called when a timed session ends by itself.
+func (bp *BinauralPlayer) OnFinished(f func()) {
+	bp.track.OnFinished(f)
+}
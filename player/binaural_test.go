@@ -0,0 +1,33 @@
+package player
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBinauralSource(t *testing.T) {
+	src := newBinauralSource(Binaural{Carrier: 200, Beat: 10, Duration: time.Second, Fade: 100 * time.Millisecond})
+	samples := readAll(t, src)
+	if got := len(samples) / 2; got != SampleRate {
+		t.Fatalf("Expected %d frames, got %d", SampleRate, got)
+	}
+
+	// Count upward zero crossings per channel over one second.
+	crossings := func(channel int) int {
+		n := 0
+		for i := channel + 2; i < len(samples); i += 2 {
+			if samples[i-2] < 0 && samples[i] >= 0 {
+				n++
+			}
+		}
+		return n
+	}
+	if l, r := crossings(0), crossings(1); math.Abs(float64(l-195)) > 1 || math.Abs(float64(r-205)) > 1 {
+		t.Errorf("Expected 195 Hz left and 205 Hz right, got %d and %d", l, r)
+	}
+
+	if last := samples[len(samples)-2]; math.Abs(float64(last)) > 1e-3 {
+		t.Errorf("Expected the session to fade out to silence, got %v", last)
+	}
+}
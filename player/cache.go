@@ -0,0 +1,156 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultCacheSize is how much decoded audio, in bytes, the default mixer
+// keeps in memory: about 45 seconds of stereo sound.
+const DefaultCacheSize = 16 << 20
+
+// bytesPerSample is the size of a decoded sample in the cache.
+const bytesPerSample = 4
+
+// ErrTooLargeToCache is returned for sounds that do not fit in what is left
+// of a cache. They are still played, from disk.
+var ErrTooLargeToCache = errors.New("sound too large to cache")
+
+// Cache keeps short sounds decoded in memory, so they start at once and
+// still play if their file is moved or deleted.
+type Cache struct {
+	mu     sync.Mutex
+	max    int64
+	size   int64
+	sounds map[string][]float32
+	gen    int
+}
+
+// NewCache returns a cache holding at most max bytes of decoded audio.
+func NewCache(max int64) *Cache {
+	return &Cache{max: max, sounds: make(map[string][]float32)}
+}
+
+// Set decodes files and makes them the content of the cache, dropping any
+// sound not among them. Files are added in order until the cache is full.
+// Files that cannot be cached are reported in the error; the others are
+// cached anyway.
+func (c *Cache) Set(files ...string) error {
+	c.mu.Lock()
+	c.gen++
+	gen := c.gen
+	old := c.sounds
+	c.mu.Unlock()
+
+	sounds := make(map[string][]float32)
+	var size int64
+	var errs []error
+	for _, file := range files {
+		if _, ok := sounds[file]; ok || file == "" {
+			continue
+		}
+		samples, ok := old[file]
+		if !ok {
+			var err error
+			samples, err = decodeFile(file, (c.max-size)/bytesPerSample)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		} else if int64(len(samples))*bytesPerSample > c.max-size {
+			errs = append(errs, fmt.Errorf("%s: %w", file, ErrTooLargeToCache))
+			continue
+		}
+		sounds[file] = samples
+		size += int64(len(samples)) * bytesPerSample
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A later call has started in the meantime; its files win.
+	if gen == c.gen {
+		c.sounds, c.size = sounds, size
+	}
+	return errors.Join(errs...)
+}
+
+// Size returns how many bytes of audio the cache holds.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Has reports whether file is cached.
+func (c *Cache) Has(file string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.sounds[file]
+	return ok
+}
+
+// source returns a source playing the cached copy of file.
+func (c *Cache) source(file string) (source, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	samples, ok := c.sounds[file]
+	if !ok {
+		return nil, false
+	}
+	return &memSource{samples: samples}, true
+}
+
+// decodeFile decodes a whole file, failing if it has more than limit
+// samples.
+func decodeFile(file string, limit int64) ([]float32, error) {
+	src, closer, err := openFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	if n := sourceLength(src) * channelCount; n > limit {
+		return nil, fmt.Errorf("%s: %w", file, ErrTooLargeToCache)
+	}
+	var samples []float32
+	buf := make([]float32, 8192)
+	for {
+		n, err := src.Read(buf)
+		samples = append(samples, buf[:n]...)
+		if int64(len(samples)) > limit {
+			return nil, fmt.Errorf("%s: %w", file, ErrTooLargeToCache)
+		}
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+}
+
+// memSource plays decoded samples. The samples are shared and never
+// modified.
+type memSource struct {
+	samples []float32
+	pos     int
+}
+
+func (s *memSource) Read(samples []float32) (int, error) {
+	if s.pos >= len(s.samples) {
+		return 0, io.EOF
+	}
+	n := copy(samples, s.samples[s.pos:])
+	s.pos += n
+	return n, nil
+}
+
+func (s *memSource) length() int64 { return int64(len(s.samples) / channelCount) }
+
+// CacheSounds replaces the sounds the default mixer keeps decoded in memory.
+// Files that do not fit are played from disk as before.
+func CacheSounds(files ...string) error {
+	return defaultMixer.Cache().Set(files...)
+}
@@ -0,0 +1,67 @@
+package player
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCachePlaysFromMemory(t *testing.T) {
+	bell := filepath.Join(t.TempDir(), "bell.wav")
+	writeWAV(t, bell, SampleRate/10, 1<<14)
+
+	rec := NewRecorder()
+	m := NewMixer()
+	m.SetOutput(rec)
+	cue := m.AddTrack(CueTrack, Cues)
+	cue.SetFade(0, 0)
+	if err := m.Cache().Set(bell); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(bell); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cue.Play(bell); err != nil {
+		t.Fatalf("Expected the cached copy to play, got %v", err)
+	}
+	if got := cue.Duration(); got != 100*time.Millisecond {
+		t.Errorf("Expected a 100ms sound, got %v", got)
+	}
+	if err := rec.Advance(50 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if samples := rec.Samples(); samples[len(samples)-1] != 1<<14 {
+		t.Errorf("Expected the bell at full level, got %d", samples[len(samples)-1])
+	}
+}
+
+func TestCacheSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	short := filepath.Join(dir, "short.wav")
+	long := filepath.Join(dir, "long.wav")
+	writeWAV(t, short, 1000, 1)
+	writeWAV(t, long, 2000, 1)
+
+	c := NewCache(2500 * channelCount * bytesPerSample)
+	err := c.Set(short, long)
+	if !errors.Is(err, ErrTooLargeToCache) {
+		t.Errorf("Expected the second file not to fit, got %v", err)
+	}
+	if !c.Has(short) || c.Has(long) {
+		t.Error("Expected only the short file to be cached")
+	}
+	if got := c.Size(); got != 1000*channelCount*bytesPerSample {
+		t.Errorf("Expected the short file's size, got %d", got)
+	}
+
+	// A new set of files replaces the old one.
+	if err := c.Set(long); err != nil {
+		t.Fatal(err)
+	}
+	if c.Has(short) || !c.Has(long) {
+		t.Error("Expected only the long file to be cached")
+	}
+}
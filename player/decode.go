@@ -0,0 +1,93 @@
+package player
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/go-mp3"
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// ErrUnsupportedFormat is returned for files that are not MP3, WAV, OGG
+// Vorbis or FLAC.
+var ErrUnsupportedFormat = errors.New("unsupported audio format")
+
+// Extensions are the file name extensions of the supported formats.
+var Extensions = []string{".mp3", ".wav", ".ogg", ".flac"}
+
+// decode detects the format of r from its first bytes and returns a stereo
+// source at SampleRate.
+func decode(r io.Reader) (source, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(12)
+
+	// The MP3 and Ogg decoders can only tell the length of a stream they can
+	// seek in, so they get r itself, rewound, when it is a file.
+	whole := func() io.Reader {
+		if rs, ok := r.(io.ReadSeeker); ok {
+			if _, err := rs.Seek(0, io.SeekStart); err == nil {
+				return rs
+			}
+		}
+		return br
+	}
+
+	var (
+		src  source
+		rate int
+		err  error
+	)
+	switch {
+	case bytes.HasPrefix(head, []byte("RIFF")) && len(head) >= 12 && string(head[8:12]) == "WAVE":
+		src, rate, err = decodeWAV(br)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		src, rate, err = decodeOgg(whole())
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		src, rate, err = decodeFLAC(br)
+	case bytes.HasPrefix(head, []byte("ID3")) || (len(head) >= 2 && head[0] == 0xFF && head[1]&0xE0 == 0xE0):
+		src, rate, err = decodeMP3(whole())
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resample(src, rate), nil
+}
+
+func decodeOgg(r io.Reader) (source, int, error) {
+	d, err := oggvorbis.NewReader(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ogg: %w (only Vorbis is supported)", err)
+	}
+	return withLength(newStereo(d.Read, d.Channels()), d.Length()), d.SampleRate(), nil
+}
+
+func decodeMP3(r io.Reader) (source, int, error) {
+	d, err := mp3.NewDecoder(r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("mp3: %w", err)
+	}
+	// go-mp3 always produces 16-bit little-endian stereo.
+	buf := make([]byte, 4096)
+	read := func(samples []float32) (int, error) {
+		want := len(samples) * 2
+		if want > len(buf) {
+			want = len(buf)
+		}
+		n, err := io.ReadFull(d, buf[:want])
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		n /= 4 // whole frames only
+		for i := 0; i < n*2; i++ {
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(buf[2*i:]))) / (1 << 15)
+		}
+		return n * 2, err
+	}
+	return withLength(sourceFunc(read), d.Length()/4), d.SampleRate(), nil
+}
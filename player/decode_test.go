@@ -0,0 +1,350 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, src source) []float32 {
+	t.Helper()
+	var out []float32
+	buf := make([]float32, 512)
+	for {
+		n, err := src.Read(buf)
+		out = append(out, buf[:n]...)
+		if err == io.EOF {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDecodeWAVMonoResampled(t *testing.T) {
+	const rate, frames = 22050, 2205
+	var data bytes.Buffer
+	for i := 0; i < frames; i++ {
+		binary.Write(&data, binary.LittleEndian, int16(10000))
+	}
+
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(36+data.Len()))
+	wav.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate * 2), uint16(2), uint16(16)} {
+		binary.Write(&wav, binary.LittleEndian, v)
+	}
+	wav.WriteString("data")
+	binary.Write(&wav, binary.LittleEndian, uint32(data.Len()))
+	wav.Write(data.Bytes())
+
+	src, err := decode(&wav)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sourceLength(src); got != SampleRate/10 {
+		t.Errorf("Expected a length of %d frames, got %d", SampleRate/10, got)
+	}
+	samples := readAll(t, src)
+
+	// 0.1 s of audio at SampleRate, give or take the interpolation edge.
+	if got := len(samples) / 2; math.Abs(float64(got-SampleRate/10)) > 2 {
+		t.Errorf("Expected about %d frames after resampling, got %d", SampleRate/10, got)
+	}
+	want := float32(10000) / (1 << 15)
+	for i, v := range samples {
+		if math.Abs(float64(v-want)) > 1e-6 {
+			t.Fatalf("Expected mono sample %v on both channels, got %v at %d", want, v, i)
+		}
+	}
+}
+
+func TestDecodeUnsupported(t *testing.T) {
+	_, err := decode(bytes.NewReader([]byte("definitely not audio")))
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestDecodeFLAC(t *testing.T) {
+	sine := func(n int, amp, period float64) []int32 {
+		s := make([]int32, n)
+		for i := range s {
+			s[i] = int32(amp * math.Sin(2*math.Pi*float64(i)/period))
+		}
+		return s
+	}
+
+	w := &bitWriter{}
+	w.write(0x664C6143, 32) // "fLaC"
+	// Last metadata block, STREAMINFO, 34 bytes.
+	w.write(1, 1)
+	w.write(0, 7)
+	w.write(34, 24)
+	w.write(4096, 16)
+	w.write(4096, 16)
+	w.write(0, 24)
+	w.write(0, 24)
+	w.write(SampleRate, 20)
+	w.write(1, 3)  // 2 channels
+	w.write(15, 5) // 16 bits
+	w.write(0, 36)
+	for i := 0; i < 16; i++ {
+		w.write(0, 8)
+	}
+
+	var left, right []int32
+
+	// Frame 0: mid/side, both channels with a fixed order-2 predictor.
+	l0, r0 := sine(1000, 12000, 50), sine(1000, 8000, 80)
+	mid, side := make([]int32, len(l0)), make([]int32, len(l0))
+	for i := range l0 {
+		mid[i], side[i] = (l0[i]+r0[i])>>1, l0[i]-r0[i]
+	}
+	writeFrameHeader(w, 0, len(l0), 10)
+	writeFixed2(w, mid, 16)
+	writeFixed2(w, side, 17)
+	w.finishFrame()
+	left, right = append(left, l0...), append(right, r0...)
+
+	// Frame 1: independent channels, constant and LPC subframes.
+	l1, r1 := make([]int32, 100), sine(100, 3000, 25)
+	for i := range l1 {
+		l1[i] = -1234
+	}
+	writeFrameHeader(w, 1, len(l1), 1)
+	w.write(0, 1)
+	w.write(0, 6) // constant
+	w.write(0, 1)
+	w.writeSigned(-1234, 16)
+	writeLPC2(w, r1, 16)
+	w.finishFrame()
+	left, right = append(left, l1...), append(right, r1...)
+
+	// Frame 2: left/side, verbatim subframes.
+	l2, r2 := sine(64, 20000, 16), sine(64, -20000, 32)
+	writeFrameHeader(w, 2, len(l2), 8)
+	writeVerbatim(w, l2, 16)
+	s2 := make([]int32, len(l2))
+	for i := range l2 {
+		s2[i] = l2[i] - r2[i]
+	}
+	writeVerbatim(w, s2, 17)
+	w.finishFrame()
+	left, right = append(left, l2...), append(right, r2...)
+
+	src, err := decode(bytes.NewReader(w.buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := readAll(t, src)
+	if len(samples) != 2*len(left) {
+		t.Fatalf("Expected %d frames, got %d", len(left), len(samples)/2)
+	}
+	for i := range left {
+		gotL := int32(math.Round(float64(samples[2*i]) * (1 << 15)))
+		gotR := int32(math.Round(float64(samples[2*i+1]) * (1 << 15)))
+		if gotL != left[i] || gotR != right[i] {
+			t.Fatalf("Frame %d: expected (%d, %d), got (%d, %d)", i, left[i], right[i], gotL, gotR)
+		}
+	}
+
+	// A damaged frame is reported instead of played.
+	w.buf[len(w.buf)-20] ^= 0x10
+	src, err = decode(bytes.NewReader(w.buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]float32, 4096)
+	for err == nil {
+		_, err = src.Read(buf)
+	}
+	if err == io.EOF || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected a checksum error for a damaged frame, got %v", err)
+	}
+}
+
+// testdata/tone.ogg comes from the tests of github.com/jfreymuth/oggvorbis
+// (MIT License).
+func TestDecodeOgg(t *testing.T) {
+	data, err := os.ReadFile("testdata/tone.ogg") // 1 s, mono, 44.1 kHz
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open("testdata/tone.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// From a file the length is known up front; from a plain stream it is not.
+	for name, r := range map[string]io.Reader{"file": f, "stream": bytes.NewBuffer(data)} {
+		t.Run(name, func(t *testing.T) {
+			src, err := decode(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sourceLength(src); name == "file" && got != SampleRate {
+				t.Errorf("Expected a length of %d frames, got %d", SampleRate, got)
+			}
+			samples := readAll(t, src)
+			if got := len(samples) / 2; got != SampleRate {
+				t.Errorf("Expected %d frames, got %d", SampleRate, got)
+			}
+			checkAudible(t, samples, true)
+		})
+	}
+}
+
+func TestDecodeMP3(t *testing.T) {
+	data, err := os.ReadFile("../media/focar/f1.mp3") // 48 kHz, with an ID3v2 tag
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without the tag and its padding the file starts with a frame sync.
+	tagSize := 10 + int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+	untagged := data[tagSize+bytes.IndexByte(data[tagSize:], 0xFF):]
+
+	for name, data := range map[string][]byte{"tagged": data, "untagged": untagged} {
+		t.Run(name, func(t *testing.T) {
+			src, err := decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			samples := readAll(t, src)
+			// 3.36 s at 48 kHz, resampled to SampleRate.
+			want := 161280 * SampleRate / 48000
+			if got := len(samples) / 2; math.Abs(float64(got-want)) > 1152 {
+				t.Errorf("Expected about %d frames, got %d", want, got)
+			}
+			checkAudible(t, samples, false)
+		})
+	}
+}
+
+// checkAudible fails unless samples are in range and not silent, and, for
+// mono input, the same on both channels.
+func checkAudible(t *testing.T, samples []float32, mono bool) {
+	t.Helper()
+	var peak float32
+	for i, v := range samples {
+		if v < -1 || v > 1 {
+			t.Fatalf("Sample %v out of range at %d", v, i)
+		}
+		if mono && i%2 == 1 && v != samples[i-1] {
+			t.Fatalf("Expected mono duplicated to both channels, got %v and %v at %d", samples[i-1], v, i)
+		}
+		peak = max(peak, v, -v)
+	}
+	if peak < 0.05 {
+		t.Errorf("Expected audible samples, peak is %v", peak)
+	}
+}
+
+func writeFrameHeader(w *bitWriter, number, blockSize int, assignment uint64) {
+	w.frame = len(w.buf)
+	w.write(0x3FFE, 14)
+	w.write(0, 2)
+	w.write(7, 4) // 16-bit block size follows
+	w.write(9, 4) // 44.1 kHz
+	w.write(assignment, 4)
+	w.write(4, 3) // 16 bits per sample
+	w.write(0, 1)
+	w.write(uint64(number), 8)
+	w.write(uint64(blockSize-1), 16)
+	w.write(0, 8) // CRC-8, not checked
+}
+
+func writeVerbatim(w *bitWriter, s []int32, bps int) {
+	w.write(0, 1)
+	w.write(1, 6)
+	w.write(0, 1)
+	for _, v := range s {
+		w.writeSigned(v, bps)
+	}
+}
+
+func writeFixed2(w *bitWriter, s []int32, bps int) {
+	w.write(0, 1)
+	w.write(8+2, 6)
+	w.write(0, 1)
+	w.writeSigned(s[0], bps)
+	w.writeSigned(s[1], bps)
+	residual := make([]int32, 0, len(s))
+	for i := 2; i < len(s); i++ {
+		residual = append(residual, s[i]-(2*s[i-1]-s[i-2]))
+	}
+	writeResidual(w, residual)
+}
+
+func writeLPC2(w *bitWriter, s []int32, bps int) {
+	w.write(0, 1)
+	w.write(32+1, 6) // order 2
+	w.write(0, 1)
+	w.writeSigned(s[0], bps)
+	w.writeSigned(s[1], bps)
+	w.write(3, 4) // 4-bit coefficients
+	w.writeSigned(1, 5)
+	w.writeSigned(4, 4) // predicts (4*s[i-1] - 2*s[i-2]) >> 1
+	w.writeSigned(-2, 4)
+	residual := make([]int32, 0, len(s))
+	for i := 2; i < len(s); i++ {
+		residual = append(residual, s[i]-((4*s[i-1]-2*s[i-2])>>1))
+	}
+	writeResidual(w, residual)
+}
+
+func writeResidual(w *bitWriter, residual []int32) {
+	const k = 6
+	w.write(0, 2) // 4-bit Rice parameters
+	w.write(0, 4) // one partition
+	w.write(k, 4)
+	for _, r := range residual {
+		u := uint64(uint32(r<<1) ^ uint32(r>>31))
+		for q := u >> k; q > 0; q-- {
+			w.write(0, 1)
+		}
+		w.write(1, 1)
+		w.write(u&(1<<k-1), k)
+	}
+}
+
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	count int
+	frame int // where the current frame starts in buf
+}
+
+func (w *bitWriter) write(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | (v>>i)&1
+		w.count++
+		if w.count == 8 {
+			w.buf = append(w.buf, byte(w.acc))
+			w.acc, w.count = 0, 0
+		}
+	}
+}
+
+func (w *bitWriter) writeSigned(v int32, n int) {
+	w.write(uint64(v)&(1<<n-1), n)
+}
+
+func (w *bitWriter) finishFrame() {
+	for w.count != 0 {
+		w.write(0, 1)
+	}
+	var crc uint16
+	for _, c := range w.buf[w.frame:] {
+		crc = crc16(crc, c)
+	}
+	w.write(uint64(crc), 16)
+}
@@ -0,0 +1,55 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hajimehoshi/oto/v2"
+)
+
+// Output format of the audio engine. Every sound is played at this rate.
+const (
+	SampleRate      = 44100
+	channelCount    = 2
+	bitDepthInBytes = 2
+)
+
+// audioContext is the part of an oto context the engine uses.
+type audioContext interface {
+	NewPlayer(r io.Reader) oto.Player
+}
+
+// engine holds the process-wide audio context. Oto supports a single context
+// per process, so it is created on first use and never closed or recreated;
+// if the audio device cannot be opened the error is returned on every call.
+type engine struct {
+	newContext func() (audioContext, error)
+
+	once sync.Once
+	ctx  audioContext
+	err  error
+}
+
+// defaultEngine is the engine of the sound card.
+var defaultEngine = &engine{newContext: newOtoContext}
+
+// context returns the audio context, creating it the first time.
+func (e *engine) context() (audioContext, error) {
+	e.once.Do(func() {
+		e.ctx, e.err = e.newContext()
+		if e.err != nil {
+			e.ctx, e.err = nil, fmt.Errorf("opening audio device: %w", e.err)
+		}
+	})
+	return e.ctx, e.err
+}
+
+func newOtoContext() (audioContext, error) {
+	ctx, ready, err := oto.NewContext(SampleRate, channelCount, bitDepthInBytes)
+	if err != nil {
+		return nil, err
+	}
+	<-ready
+	return ctx, nil
+}
@@ -0,0 +1,116 @@
+package player
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hajimehoshi/oto/v2"
+)
+
+// fakeContext stands in for the oto context. Its players only record that
+// they were started.
+type fakeContext struct {
+	mu      sync.Mutex
+	players int
+}
+
+func (c *fakeContext) NewPlayer(r io.Reader) oto.Player {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.players++
+	return &fakePlayer{}
+}
+
+type fakePlayer struct {
+	oto.Player // only Play is called
+	playing    bool
+}
+
+func (p *fakePlayer) Play() { p.playing = true }
+
+// fakeEngine returns an engine built on ctx, or failing with err, and a
+// count of the contexts it created.
+func fakeEngine(ctx *fakeContext, err error) (*engine, *int) {
+	created := 0
+	return &engine{newContext: func() (audioContext, error) {
+		created++
+		if err != nil {
+			return nil, err
+		}
+		return ctx, nil
+	}}, &created
+}
+
+func TestEngineCreatesContextOnce(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cue.wav")
+	writeWAV(t, file, SampleRate/10, 1<<14)
+	ctx := &fakeContext{}
+	e, created := fakeEngine(ctx, nil)
+
+	// The focus cue, then the break cue, on two mixers sharing the device.
+	for _, m := range []*Mixer{NewMixer(), NewMixer()} {
+		m.SetOutput(deviceOutput{engine: e})
+		cue := m.AddTrack(CueTrack, Cues)
+		for i := 0; i < 2; i++ {
+			if err := cue.Play(file); err != nil {
+				t.Fatalf("Play %d: %v", i+1, err)
+			}
+		}
+	}
+	if *created != 1 {
+		t.Errorf("Expected the context to be created once, got %d", *created)
+	}
+	if ctx.players != 2 {
+		t.Errorf("Expected one output stream per mixer, got %d", ctx.players)
+	}
+}
+
+func TestEngineStopAndIsPlaying(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cue.wav")
+	writeWAV(t, file, SampleRate, 1<<14)
+	e, _ := fakeEngine(&fakeContext{}, nil)
+	m := NewMixer()
+	m.SetOutput(deviceOutput{engine: e})
+	cue := m.AddTrack(CueTrack, Cues)
+
+	if cue.IsPlaying() {
+		t.Error("Expected nothing playing before Play")
+	}
+	if err := cue.Play(file); err != nil {
+		t.Fatal(err)
+	}
+	if !cue.IsPlaying() {
+		t.Error("Expected the cue to play")
+	}
+	cue.Stop()
+	if cue.IsPlaying() {
+		t.Error("Expected the cue to stop")
+	}
+}
+
+func TestEngineReturnsErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cue.wav")
+	writeWAV(t, file, SampleRate/10, 1<<14)
+	noDevice := errors.New("no audio device")
+	e, created := fakeEngine(nil, noDevice)
+
+	for _, m := range []*Mixer{NewMixer(), NewMixer()} {
+		m.SetOutput(deviceOutput{engine: e})
+		if err := m.AddTrack(CueTrack, Cues).Play(file); !errors.Is(err, noDevice) {
+			t.Errorf("Expected the device error, got %v", err)
+		}
+	}
+	if *created != 1 {
+		t.Errorf("Expected a failed context not to be retried, got %d attempts", *created)
+	}
+
+	e, _ = fakeEngine(&fakeContext{}, nil)
+	m := NewMixer()
+	m.SetOutput(deviceOutput{engine: e})
+	if err := m.AddTrack(CueTrack, Cues).Play(filepath.Join(t.TempDir(), "missing.wav")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,117 @@
+package player
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// fader applies the category volume and a fade envelope to a source. Gain
+// changes are ramped per sample so starts, stops and volume changes never
+// click.
+type fader struct {
+	src      source
+	category Category
+
+	mu     sync.Mutex
+	gain   float32 // current envelope, 0..1
+	target float32
+	step   float32 // envelope change per frame
+	ending bool    // fading out, the sound ends once silent
+	done   func()  // called once the sound has ended
+	over   bool
+}
+
+func newFader(src source, category Category, fadeIn time.Duration) *fader {
+	f := &fader{src: src, category: category, gain: 1, target: 1}
+	if fadeIn > 0 {
+		f.gain = 0
+		f.step = rampStep(fadeIn)
+	}
+	return f
+}
+
+// fadeOut ramps the sound down over d and then ends it. done runs once the
+// sound is silent, on its own goroutine.
+func (f *fader) fadeOut(d time.Duration, done func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.target = 0
+	f.step = rampStep(d)
+	f.ending = true
+	f.done = done
+	if d <= 0 {
+		f.gain = 0
+	}
+}
+
+// fadeTo ramps the envelope towards level, moving at most the full range in
+// d. A level of 0 silences the sound without ending it.
+func (f *fader) fadeTo(level float32, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ending {
+		return
+	}
+	f.target = level
+	f.step = rampStep(d)
+}
+
+// silent reports whether the envelope has settled at 0.
+func (f *fader) silent() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gain == 0 && f.target == 0
+}
+
+func rampStep(d time.Duration) float32 {
+	frames := d.Seconds() * SampleRate
+	if frames < 1 {
+		return 1
+	}
+	return float32(1 / frames)
+}
+
+func (f *fader) Read(samples []float32) (int, error) {
+	f.mu.Lock()
+	if f.over {
+		f.mu.Unlock()
+		return 0, io.EOF
+	}
+	f.mu.Unlock()
+
+	n, err := f.src.Read(samples)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	vol := volume(f.category)
+	for i := 0; i+1 < n; i += 2 {
+		switch {
+		case f.gain < f.target:
+			f.gain = min(f.gain+f.step, f.target)
+		case f.gain > f.target:
+			f.gain = max(f.gain-f.step, f.target)
+		}
+		g := f.gain * vol
+		samples[i] *= g
+		samples[i+1] *= g
+		if f.ending && f.gain == 0 {
+			// Faded out: end the stream here.
+			n = i + 2
+			f.finish()
+			return n, io.EOF
+		}
+	}
+	if err != nil && f.done != nil {
+		f.finish()
+	}
+	return n, err
+}
+
+func (f *fader) finish() {
+	f.over = true
+	if f.done != nil {
+		go f.done()
+		f.done = nil
+	}
+}
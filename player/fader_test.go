@@ -0,0 +1,57 @@
+package player
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// constant is an endless source of full-scale samples.
+type constant struct{}
+
+func (constant) Read(samples []float32) (int, error) {
+	for i := range samples {
+		samples[i] = 1
+	}
+	return len(samples), nil
+}
+
+func TestFaderVolumeAndFades(t *testing.T) {
+	SetMasterVolume(0.5)
+	SetVolume(Ambience, 0.5)
+	defer SetMasterVolume(1)
+	defer SetVolume(Ambience, 1)
+
+	f := newFader(constant{}, Ambience, 10*time.Millisecond)
+	buf := make([]float32, SampleRate/100*2)
+	if _, err := f.Read(buf); err != nil {
+		t.Fatal(err)
+	}
+	for i := 2; i < len(buf); i += 2 {
+		if buf[i] < buf[i-2] {
+			t.Fatalf("Expected a rising fade-in, sample %d dropped from %v to %v", i, buf[i-2], buf[i])
+		}
+	}
+	if last := buf[len(buf)-1]; last < 0.24 || last > 0.25 {
+		t.Errorf("Expected the fade-in to end at master*category volume 0.25, got %v", last)
+	}
+
+	done := make(chan struct{})
+	f.fadeOut(10*time.Millisecond, func() { close(done) })
+	total := 0
+	for {
+		n, err := f.Read(buf)
+		total += n
+		if err == io.EOF {
+			break
+		}
+		if total > SampleRate {
+			t.Fatal("Expected the fade-out to end the stream")
+		}
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected the fade-out callback to run")
+	}
+}
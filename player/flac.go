@@ -0,0 +1,517 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A small FLAC decoder covering the whole format as produced by common
+// encoders: constant, verbatim, fixed and LPC subframes, both Rice residual
+// codings and all stereo decorrelation modes. Each frame's CRC-16 is
+// verified; the MD5 signature of the whole stream is not.
+
+type flacDecoder struct {
+	br *bitReader
+
+	rate          int
+	channels      int
+	bitsPerSample int
+	total         int64 // frames in the stream, 0 if unknown
+
+	block [][]int32 // decoded samples of the current frame, per channel
+	pos   int       // next sample to return from block
+	err   error
+}
+
+func decodeFLAC(r io.Reader) (source, int, error) {
+	br := &bitReader{r: bufio.NewReader(r)}
+	marker, err := br.bits(32)
+	if err != nil {
+		return nil, 0, fmt.Errorf("flac: %w", err)
+	}
+	if marker != 0x664C6143 { // "fLaC"
+		return nil, 0, errors.New("flac: missing stream marker")
+	}
+
+	d := &flacDecoder{br: br}
+	haveInfo := false
+	for last := false; !last; {
+		header, err := br.bits(32)
+		if err != nil {
+			return nil, 0, fmt.Errorf("flac: %w", err)
+		}
+		last = header>>31 == 1
+		blockType := (header >> 24) & 0x7F
+		length := int(header & 0xFFFFFF)
+
+		if blockType == 0 { // STREAMINFO
+			if length < 34 {
+				return nil, 0, errors.New("flac: short STREAMINFO")
+			}
+			br.bits(16) // min block size
+			br.bits(16) // max block size
+			br.bits(24) // min frame size
+			br.bits(24) // max frame size
+			rate, _ := br.bits(20)
+			channels, _ := br.bits(3)
+			bps, _ := br.bits(5)
+			total, _ := br.bits(36)
+			// Skip the MD5 signature and anything after it.
+			if err := br.skipBytes(16 + length - 34); err != nil {
+				return nil, 0, fmt.Errorf("flac: %w", err)
+			}
+			d.rate = int(rate)
+			d.channels = int(channels) + 1
+			d.bitsPerSample = int(bps) + 1
+			d.total = int64(total)
+			haveInfo = true
+			continue
+		}
+		if err := br.skipBytes(length); err != nil {
+			return nil, 0, fmt.Errorf("flac: %w", err)
+		}
+	}
+	if !haveInfo {
+		return nil, 0, errors.New("flac: missing STREAMINFO")
+	}
+	if d.rate == 0 {
+		return nil, 0, errors.New("flac: invalid sample rate")
+	}
+
+	return withLength(newStereo(d.read, d.channels), d.total), d.rate, nil
+}
+
+func (d *flacDecoder) read(samples []float32) (int, error) {
+	n := 0
+	scale := float32(int64(1) << (d.bitsPerSample - 1))
+	for n+d.channels <= len(samples) {
+		if d.block == nil || d.pos >= len(d.block[0]) {
+			if d.err != nil {
+				return n, d.err
+			}
+			if err := d.readFrame(); err != nil {
+				d.err = err
+				return n, err
+			}
+			continue
+		}
+		for c := 0; c < d.channels; c++ {
+			samples[n+c] = float32(d.block[c][d.pos]) / scale
+		}
+		d.pos++
+		n += d.channels
+	}
+	return n, nil
+}
+
+var flacSampleSizes = [...]int{0, 8, 12, 0, 16, 20, 24, 32}
+
+func (d *flacDecoder) readFrame() error {
+	br := d.br
+	br.align()
+	br.crc = 0
+
+	sync, err := br.bits(14)
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		return io.EOF
+	}
+	if err != nil {
+		return err
+	}
+	if sync != 0x3FFE {
+		return errors.New("flac: lost frame sync")
+	}
+	br.bits(1) // reserved
+	br.bits(1) // blocking strategy
+	blockSizeCode, _ := br.bits(4)
+	rateCode, _ := br.bits(4)
+	assignment, _ := br.bits(4)
+	sizeCode, _ := br.bits(3)
+	br.bits(1) // reserved
+	if err := br.skipUTF8(); err != nil {
+		return err
+	}
+
+	var blockSize int
+	switch {
+	case blockSizeCode == 1:
+		blockSize = 192
+	case blockSizeCode >= 2 && blockSizeCode <= 5:
+		blockSize = 576 << (blockSizeCode - 2)
+	case blockSizeCode == 6:
+		v, _ := br.bits(8)
+		blockSize = int(v) + 1
+	case blockSizeCode == 7:
+		v, _ := br.bits(16)
+		blockSize = int(v) + 1
+	case blockSizeCode >= 8:
+		blockSize = 256 << (blockSizeCode - 8)
+	default:
+		return errors.New("flac: reserved block size")
+	}
+
+	switch rateCode {
+	case 12:
+		br.bits(8)
+	case 13, 14:
+		br.bits(16)
+	case 15:
+		return errors.New("flac: invalid sample rate code")
+	}
+	// Frames may only restate the stream's rate; playback uses STREAMINFO.
+
+	bps := d.bitsPerSample
+	if sizeCode != 0 {
+		if flacSampleSizes[sizeCode] == 0 {
+			return errors.New("flac: reserved sample size")
+		}
+		bps = flacSampleSizes[sizeCode]
+	}
+
+	br.bits(8) // CRC-8
+
+	channels := int(assignment) + 1
+	if assignment >= 8 {
+		if assignment > 10 {
+			return errors.New("flac: reserved channel assignment")
+		}
+		channels = 2
+	}
+	if channels != d.channels {
+		return errors.New("flac: channel count changed mid-stream")
+	}
+
+	if len(d.block) != channels || cap(d.block[0]) < blockSize {
+		d.block = make([][]int32, channels)
+		for c := range d.block {
+			d.block[c] = make([]int32, blockSize)
+		}
+	}
+	for c := range d.block {
+		d.block[c] = d.block[c][:blockSize]
+		channelBps := bps
+		// The side channel carries one extra bit.
+		if (assignment == 8 && c == 1) || (assignment == 9 && c == 0) || (assignment == 10 && c == 1) {
+			channelBps++
+		}
+		if err := d.readSubframe(d.block[c], channelBps); err != nil {
+			return err
+		}
+	}
+
+	left, right := d.block[0], d.block[len(d.block)-1]
+	switch assignment {
+	case 8: // left/side
+		for i := range left {
+			right[i] = left[i] - right[i]
+		}
+	case 9: // side/right
+		for i := range left {
+			left[i] += right[i]
+		}
+	case 10: // mid/side
+		for i := range left {
+			side := right[i]
+			mid := left[i]<<1 | side&1
+			left[i] = (mid + side) >> 1
+			right[i] = (mid - side) >> 1
+		}
+	}
+
+	// Rescale if this frame's sample size differs from the stream's.
+	if shift := d.bitsPerSample - bps; shift != 0 {
+		for _, ch := range d.block {
+			for i := range ch {
+				if shift > 0 {
+					ch[i] <<= shift
+				} else {
+					ch[i] >>= -shift
+				}
+			}
+		}
+	}
+
+	br.align()
+	crc := br.crc
+	want, err := br.bits(16)
+	if err != nil {
+		return err
+	}
+	if uint16(want) != crc {
+		return errors.New("flac: frame checksum mismatch")
+	}
+	d.pos = 0
+	return nil
+}
+
+func (d *flacDecoder) readSubframe(out []int32, bps int) error {
+	br := d.br
+	if _, err := br.bits(1); err != nil { // zero padding
+		return err
+	}
+	kind, _ := br.bits(6)
+	wasted := 0
+	if flag, _ := br.bits(1); flag == 1 {
+		k, err := br.unary()
+		if err != nil {
+			return err
+		}
+		wasted = k + 1
+		bps -= wasted
+	}
+
+	switch {
+	case kind == 0: // constant
+		v, err := br.signed(bps)
+		if err != nil {
+			return err
+		}
+		for i := range out {
+			out[i] = v
+		}
+	case kind == 1: // verbatim
+		for i := range out {
+			v, err := br.signed(bps)
+			if err != nil {
+				return err
+			}
+			out[i] = v
+		}
+	case kind >= 8 && kind <= 12: // fixed predictor
+		order := int(kind - 8)
+		if err := d.readWarmup(out, order, bps); err != nil {
+			return err
+		}
+		if err := d.readResidual(out, order); err != nil {
+			return err
+		}
+		fixedPredict(out, order)
+	case kind >= 32: // LPC
+		order := int(kind-32) + 1
+		if err := d.readWarmup(out, order, bps); err != nil {
+			return err
+		}
+		precision, _ := br.bits(4)
+		if precision == 15 {
+			return errors.New("flac: invalid LPC precision")
+		}
+		shift, err := br.signed(5)
+		if err != nil {
+			return err
+		}
+		coeffs := make([]int32, order)
+		for i := range coeffs {
+			if coeffs[i], err = br.signed(int(precision) + 1); err != nil {
+				return err
+			}
+		}
+		if err := d.readResidual(out, order); err != nil {
+			return err
+		}
+		lpcPredict(out, coeffs, int(shift))
+	default:
+		return fmt.Errorf("flac: reserved subframe type %d", kind)
+	}
+
+	if wasted > 0 {
+		for i := range out {
+			out[i] <<= wasted
+		}
+	}
+	return nil
+}
+
+func (d *flacDecoder) readWarmup(out []int32, order, bps int) error {
+	if order > len(out) {
+		return errors.New("flac: predictor order exceeds block size")
+	}
+	for i := 0; i < order; i++ {
+		v, err := d.br.signed(bps)
+		if err != nil {
+			return err
+		}
+		out[i] = v
+	}
+	return nil
+}
+
+// readResidual decodes the Rice-coded residual into out[order:].
+func (d *flacDecoder) readResidual(out []int32, order int) error {
+	br := d.br
+	method, _ := br.bits(2)
+	if method > 1 {
+		return errors.New("flac: reserved residual coding method")
+	}
+	paramBits, escape := 4, uint64(15)
+	if method == 1 {
+		paramBits, escape = 5, 31
+	}
+	partitionOrder, err := br.bits(4)
+	if err != nil {
+		return err
+	}
+	partitions := 1 << partitionOrder
+	partitionSize := len(out) >> partitionOrder
+
+	i := order
+	for p := 0; p < partitions; p++ {
+		count := partitionSize
+		if p == 0 {
+			count -= order
+		}
+		if count < 0 || i+count > len(out) {
+			return errors.New("flac: invalid residual partition")
+		}
+		param, err := br.bits(paramBits)
+		if err != nil {
+			return err
+		}
+		if param == escape {
+			rawBits, _ := br.bits(5)
+			for j := 0; j < count; j++ {
+				v, err := br.signed(int(rawBits))
+				if err != nil {
+					return err
+				}
+				out[i] = v
+				i++
+			}
+			continue
+		}
+		for j := 0; j < count; j++ {
+			q, err := br.unary()
+			if err != nil {
+				return err
+			}
+			r, err := br.bits(int(param))
+			if err != nil {
+				return err
+			}
+			v := uint32(q)<<param | uint32(r)
+			out[i] = int32(v>>1) ^ -int32(v&1)
+			i++
+		}
+	}
+	return nil
+}
+
+func fixedPredict(s []int32, order int) {
+	for i := order; i < len(s); i++ {
+		switch order {
+		case 1:
+			s[i] += s[i-1]
+		case 2:
+			s[i] += 2*s[i-1] - s[i-2]
+		case 3:
+			s[i] += 3*s[i-1] - 3*s[i-2] + s[i-3]
+		case 4:
+			s[i] += 4*s[i-1] - 6*s[i-2] + 4*s[i-3] - s[i-4]
+		}
+	}
+}
+
+func lpcPredict(s []int32, coeffs []int32, shift int) {
+	for i := len(coeffs); i < len(s); i++ {
+		var sum int64
+		for j, c := range coeffs {
+			sum += int64(c) * int64(s[i-1-j])
+		}
+		s[i] += int32(sum >> shift)
+	}
+}
+
+// bitReader reads big-endian bit fields, as used throughout FLAC.
+type bitReader struct {
+	r     *bufio.Reader
+	acc   uint64
+	count int
+	crc   uint16 // CRC-16 of the bytes read since it was last reset
+}
+
+func (b *bitReader) bits(n int) (uint64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	for b.count < n {
+		c, err := b.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && b.count > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		b.acc = b.acc<<8 | uint64(c)
+		b.count += 8
+		b.crc = crc16(b.crc, c)
+	}
+	v := (b.acc >> (b.count - n)) & (1<<n - 1)
+	b.count -= n
+	return v, nil
+}
+
+// crc16 adds c to crc, the CRC-16 used by FLAC frames (polynomial 0x8005).
+func crc16(crc uint16, c byte) uint16 {
+	crc ^= uint16(c) << 8
+	for i := 0; i < 8; i++ {
+		if crc&0x8000 != 0 {
+			crc = crc<<1 ^ 0x8005
+		} else {
+			crc <<= 1
+		}
+	}
+	return crc
+}
+
+func (b *bitReader) signed(n int) (int32, error) {
+	v, err := b.bits(n)
+	if err != nil || n == 0 {
+		return 0, err
+	}
+	return int32(int64(v<<(64-n)) >> (64 - n)), nil
+}
+
+// unary counts zero bits up to the next one bit.
+func (b *bitReader) unary() (int, error) {
+	n := 0
+	for {
+		bit, err := b.bits(1)
+		if err != nil {
+			return 0, err
+		}
+		if bit == 1 {
+			return n, nil
+		}
+		n++
+	}
+}
+
+func (b *bitReader) align() {
+	b.count -= b.count % 8
+}
+
+func (b *bitReader) skipBytes(n int) error {
+	for ; n > 0 && b.count >= 8; n-- {
+		b.count -= 8
+	}
+	_, err := b.r.Discard(n)
+	return err
+}
+
+// skipUTF8 skips the UTF-8 style coded frame or sample number.
+func (b *bitReader) skipUTF8() error {
+	first, err := b.bits(8)
+	if err != nil {
+		return err
+	}
+	extra := 0
+	for mask := uint64(0x80); first&mask != 0 && mask > 1; mask >>= 1 {
+		extra++
+	}
+	if extra > 0 {
+		extra--
+	}
+	_, err = b.bits(8 * extra)
+	return err
+}
@@ -0,0 +1,180 @@
+package player
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Names of the tracks of the default mixer.
+const (
+	CueTrack        = "cue"
+	AmbienceTrack   = "ambience"
+	MeditationTrack = "meditation"
+	PlaylistTrack   = "playlist"
+	TickTrack       = "tick"
+)
+
+// Ducking lowers a track to duckLevel of its volume while another track
+// plays, quickly on the way down and gently on the way back up.
+const (
+	duckLevel   = 0.2
+	duckAttack  = 150 * time.Millisecond
+	duckRelease = time.Second
+)
+
+// outputBuffer is how much mixed audio is queued ahead of the device. It
+// bounds how late a cue starts after Play.
+const outputBuffer = 50 * time.Millisecond
+
+// Mixer sums any number of named tracks into a single output stream, so
+// cues, ambience and meditation audio play together.
+type Mixer struct {
+	mu     sync.Mutex
+	tracks []*Track
+	ducks  map[*Track][]*Track // track -> tracks it is ducked under
+	cache  *Cache
+
+	trackBuf []float32
+	voiceBuf []float32
+
+	outMu     sync.Mutex
+	output    Output
+	startOnce sync.Once
+	startErr  error
+	frames    atomic.Int64 // frames mixed since the output started
+}
+
+func NewMixer() *Mixer {
+	return &Mixer{ducks: make(map[*Track][]*Track), cache: NewCache(DefaultCacheSize)}
+}
+
+// Cache returns the sounds the mixer keeps decoded. Files found there are
+// played from memory instead of being read from disk.
+func (m *Mixer) Cache() *Cache {
+	return m.cache
+}
+
+// AddTrack adds a track whose volume follows category c. If the mixer
+// already has a track with that name, it is returned instead.
+func (m *Mixer) AddTrack(name string, c Category) *Track {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tracks {
+		if t.name == name {
+			return t
+		}
+	}
+	t := newTrack(m, name, c)
+	m.tracks = append(m.tracks, t)
+	return t
+}
+
+// Track returns the track called name, or nil.
+func (m *Mixer) Track(name string) *Track {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tracks {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Duck lowers track name whenever track under is playing.
+func (m *Mixer) Duck(name, under string) {
+	t, u := m.Track(name), m.Track(under)
+	if t == nil || u == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ducks[t] = append(m.ducks[t], u)
+}
+
+// SetOutput makes the mixer play through o instead of the sound card. It
+// has no effect once something has been played.
+func (m *Mixer) SetOutput(o Output) {
+	m.outMu.Lock()
+	defer m.outMu.Unlock()
+	m.output = o
+}
+
+// start opens the output stream the first time something is played. The
+// stream then keeps running, playing silence while every track is idle.
+func (m *Mixer) start() error {
+	m.startOnce.Do(func() {
+		m.outMu.Lock()
+		if m.output == nil {
+			m.output = DeviceOutput()
+		}
+		out := m.output
+		m.outMu.Unlock()
+		m.startErr = out.Start(newPCMReader(m))
+	})
+	return m.startErr
+}
+
+// record passes what track did to the output, if it wants to know.
+func (m *Mixer) record(track string, kind EventKind, sound string) {
+	m.recordAt(0, track, kind, sound)
+}
+
+// recordAt is record for something that happened offset frames into the
+// samples being mixed.
+func (m *Mixer) recordAt(offset int64, track string, kind EventKind, sound string) {
+	m.outMu.Lock()
+	r, ok := m.output.(recorder)
+	m.outMu.Unlock()
+	if ok {
+		r.record(Event{At: framesToDuration(m.frames.Load() + offset), Track: track, Kind: kind, Sound: sound})
+	}
+}
+
+// Read mixes the next samples of every track. It never ends.
+func (m *Mixer) Read(samples []float32) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(samples) &^ 1
+	samples = samples[:n]
+	clear(samples)
+	if cap(m.trackBuf) < n {
+		m.trackBuf = make([]float32, n)
+		m.voiceBuf = make([]float32, n)
+	}
+	for _, t := range m.tracks {
+		duck := float32(1)
+		for _, u := range m.ducks[t] {
+			if u.IsPlaying() {
+				duck = duckLevel
+			}
+		}
+		t.mix(samples, m.trackBuf[:n], m.voiceBuf[:n], duck)
+	}
+	m.frames.Add(int64(n / 2))
+	return n, nil
+}
+
+// defaultMixer has a track for each category plus one for the playlist,
+// which is ambience too, and one for the clock tick, which is a cue. Ambience
+// is ducked while a cue plays.
+var defaultMixer = func() *Mixer {
+	m := NewMixer()
+	m.AddTrack(CueTrack, Cues).SetFade(cueFadeIn, cueFadeOut)
+	m.AddTrack(AmbienceTrack, Ambience)
+	m.AddTrack(MeditationTrack, Meditation)
+	m.AddTrack(PlaylistTrack, Ambience)
+	m.AddTrack(TickTrack, Cues).SetFade(0, 0)
+	m.Duck(AmbienceTrack, CueTrack)
+	m.Duck(PlaylistTrack, CueTrack)
+	return m
+}()
+
+// DefaultMixer returns the mixer behind the package-level functions, with
+// the tracks CueTrack, AmbienceTrack, MeditationTrack, PlaylistTrack and
+// TickTrack.
+func DefaultMixer() *Mixer {
+	return defaultMixer
+}
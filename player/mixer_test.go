@@ -0,0 +1,135 @@
+package player
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMixerDucksAmbienceUnderCue(t *testing.T) {
+	m := NewMixer()
+	cue := m.AddTrack("cue", Cues)
+	ambience := m.AddTrack("ambience", Ambience)
+	m.Duck("ambience", "cue")
+	cue.SetFade(0, 0)
+	ambience.SetFade(0, 0)
+
+	buf := make([]float32, SampleRate/2*2)
+	ambience.start("constant", constant{}, nil)
+	m.Read(buf)
+	if got := buf[len(buf)-1]; got != 1 {
+		t.Fatalf("Expected ambience alone at full volume, got %v", got)
+	}
+
+	// Half a second is well past the duck attack: 1 from the cue plus the
+	// ducked ambience.
+	cue.start("constant", constant{}, nil)
+	m.Read(buf)
+	if got := buf[len(buf)-1]; math.Abs(float64(got-(1+duckLevel))) > 1e-4 {
+		t.Errorf("Expected cue and ducked ambience to sum to %v, got %v", 1+duckLevel, got)
+	}
+
+	cue.Stop()
+	for i := 0; i < 4; i++ {
+		m.Read(buf)
+	}
+	if cue.IsPlaying() || !ambience.IsPlaying() {
+		t.Fatal("Expected only the ambience to be playing")
+	}
+	if got := buf[len(buf)-1]; got != 1 {
+		t.Errorf("Expected ambience back at full volume after the cue, got %v", got)
+	}
+}
+
+// writeWAV writes a 16-bit stereo WAV file of frames identical samples.
+func writeWAV(t *testing.T, file string, frames int, value int16) {
+	t.Helper()
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	binary.Write(&wav, binary.LittleEndian, uint32(36+frames*4))
+	wav.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(2), uint32(SampleRate), uint32(SampleRate * 4), uint16(4), uint16(16)} {
+		binary.Write(&wav, binary.LittleEndian, v)
+	}
+	wav.WriteString("data")
+	binary.Write(&wav, binary.LittleEndian, uint32(frames*4))
+	for i := 0; i < frames*2; i++ {
+		binary.Write(&wav, binary.LittleEndian, value)
+	}
+	if err := os.WriteFile(file, wav.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrackLoops(t *testing.T) {
+	const frames = 1000
+	file := filepath.Join(t.TempDir(), "loop.wav")
+	writeWAV(t, file, frames, 1<<14)
+
+	m := NewMixer()
+	track := m.AddTrack("ambience", Ambience)
+	track.SetFade(0, 0)
+	track.SetLoop(true)
+	if err := track.play(file); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]float32, frames*2*5/2)
+	m.Read(buf)
+	for i, v := range buf {
+		if v != 0.5 {
+			t.Fatalf("Expected the file to repeat without gaps, got %v at sample %d", v, i)
+		}
+	}
+	if !track.IsPlaying() {
+		t.Error("Expected a looping track to keep playing")
+	}
+}
+
+func TestTrackStatus(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cue.wav")
+	writeWAV(t, file, SampleRate/10, 1<<14)
+
+	m := NewMixer()
+	track := m.AddTrack("cue", Cues)
+	finished := make(chan struct{}, 1)
+	track.OnFinished(func() { finished <- struct{}{} })
+	if err := track.play(file); err != nil {
+		t.Fatal(err)
+	}
+	if got := track.Duration(); got != 100*time.Millisecond {
+		t.Errorf("Expected a duration of 100ms, got %v", got)
+	}
+
+	buf := make([]float32, SampleRate/20*2)
+	m.Read(buf)
+	if got := track.Position(); got != 50*time.Millisecond || !track.IsPlaying() {
+		t.Errorf("Expected to be playing at 50ms, got %v (playing %v)", got, track.IsPlaying())
+	}
+
+	m.Read(buf)
+	m.Read(buf)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("Expected OnFinished once the sound ended")
+	}
+	if track.IsPlaying() || track.Position() != 0 {
+		t.Errorf("Expected nothing playing after the end, got position %v", track.Position())
+	}
+
+	// Stopping is not finishing.
+	track.play(file)
+	track.Stop()
+	m.Read(buf)
+	m.Read(buf)
+	select {
+	case <-finished:
+		t.Error("Expected no OnFinished call for a stopped sound")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,170 @@
+package player
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Noise is a procedurally generated ambience.
+type Noise string
+
+const (
+	NoiseOff   Noise = "off"
+	NoiseWhite Noise = "white"
+	NoisePink  Noise = "pink"
+	NoiseBrown Noise = "brown"
+	NoiseRain  Noise = "rain" // filtered noise with droplets
+)
+
+// Noises lists the available noises, in the order they are offered.
+var Noises = []Noise{NoiseWhite, NoisePink, NoiseBrown, NoiseRain}
+
+// noiseGain keeps the noise well below full scale; noise at the same peak
+// level as a cue sounds much louder.
+const noiseGain = 0.3
+
+// noiseChannel generates one channel. The channels get independent
+// generators so the noise sounds wide instead of centred.
+type noiseChannel struct {
+	rng *rand.Rand
+
+	b0, b1, b2 float64 // pink filter state
+	level      float64 // brown integrator
+	low, prev  float64 // rain filter state
+	drop       float64 // envelope of the current rain drop
+}
+
+func (c *noiseChannel) white() float64 {
+	return c.rng.Float64()*2 - 1
+}
+
+// pink filters white noise down by 3 dB per octave (Paul Kellet's economy
+// filter).
+func (c *noiseChannel) pink() float64 {
+	w := c.white()
+	c.b0 = 0.99765*c.b0 + w*0.0990460
+	c.b1 = 0.96300*c.b1 + w*0.2965164
+	c.b2 = 0.57000*c.b2 + w*1.0526913
+	return (c.b0 + c.b1 + c.b2 + w*0.1848) * 0.25
+}
+
+// brown integrates white noise with a slight leak so it never drifts off.
+func (c *noiseChannel) brown() float64 {
+	c.level = (c.level + 0.02*c.white()) / 1.02
+	return c.level * 7
+}
+
+// rain is a soft hiss of band-limited noise with sparse, quickly decaying
+// bursts on top for the drops.
+func (c *noiseChannel) rain() float64 {
+	w := c.white()
+	c.low += 0.3 * (w - c.low) // low-pass around 2.5 kHz
+	hiss := c.low - c.prev     // then high-pass to drop the rumble
+	c.prev = c.low
+	if c.rng.IntN(SampleRate/40) == 0 { // about 40 drops a second
+		c.drop = 0.5 + c.rng.Float64()*0.5
+	}
+	c.drop *= 0.996
+	return hiss*1.6 + w*c.drop*0.8
+}
+
+type noiseSource struct {
+	next        func(*noiseChannel) float64
+	left, right noiseChannel
+}
+
+func newNoiseSource(n Noise, seed uint64) source {
+	s := &noiseSource{
+		left:  noiseChannel{rng: rand.New(rand.NewPCG(seed, 1))},
+		right: noiseChannel{rng: rand.New(rand.NewPCG(seed, 2))},
+	}
+	switch n {
+	case NoisePink:
+		s.next = (*noiseChannel).pink
+	case NoiseBrown:
+		s.next = (*noiseChannel).brown
+	case NoiseRain:
+		s.next = (*noiseChannel).rain
+	default:
+		s.next = (*noiseChannel).white
+	}
+	return s
+}
+
+func (s *noiseSource) Read(samples []float32) (int, error) {
+	n := 0
+	for ; n+1 < len(samples); n += 2 {
+		samples[n] = float32(clampSample(s.next(&s.left) * noiseGain))
+		samples[n+1] = float32(clampSample(s.next(&s.right) * noiseGain))
+	}
+	return n, nil
+}
+
+func clampSample(v float64) float64 {
+	return max(-1, min(1, v))
+}
+
+// breakFade is how long the noise takes to duck for a break and to come
+// back afterwards.
+const breakFade = time.Second
+
+// NoisePlayer plays a noise ambience endlessly until stopped. Its volume
+// follows the Ambience category.
+type NoisePlayer struct {
+	track *Track
+
+	mu      sync.Mutex
+	current Noise
+	ducked  bool
+}
+
+// NewNoisePlayer returns a player on the ambience track of the default
+// mixer.
+func NewNoisePlayer() *NoisePlayer {
+	return &NoisePlayer{track: defaultMixer.Track(AmbienceTrack), current: NoiseOff}
+}
+
+// Play plays noise n at full volume. A noise that is already playing keeps
+// going and is only brought back up if it was ducked; NoiseOff stops.
+func (np *NoisePlayer) Play(n Noise) error {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	if n == NoiseOff || n == "" {
+		np.stop()
+		return nil
+	}
+	if n == np.current && np.track.IsPlaying() {
+		if np.ducked {
+			np.track.fadeTo(1, breakFade)
+			np.ducked = false
+		}
+		return nil
+	}
+	if err := np.track.playSource("noise:"+string(n), newNoiseSource(n, rand.Uint64()), nil); err != nil {
+		return err
+	}
+	np.current, np.ducked = n, false
+	return nil
+}
+
+// Duck lowers the noise without stopping it, e.g. during a break.
+func (np *NoisePlayer) Duck() {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	if np.current != NoiseOff && !np.ducked {
+		np.track.fadeTo(duckLevel, breakFade)
+		np.ducked = true
+	}
+}
+
+func (np *NoisePlayer) Stop() {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	np.stop()
+}
+
+func (np *NoisePlayer) stop() {
+	np.track.Stop()
+	np.current, np.ducked = NoiseOff, false
+}
@@ -0,0 +1,40 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoiseColors(t *testing.T) {
+	// The darker the noise, the smaller the step between consecutive samples
+	// relative to its level.
+	roughness := func(n Noise) float64 {
+		src := newNoiseSource(n, 42)
+		samples := make([]float32, 2*SampleRate)
+		if got, err := src.Read(samples); err != nil || got != len(samples) {
+			t.Fatalf("%s: read %d samples, err %v", n, got, err)
+		}
+		var sum, diff float64
+		for i := 0; i < len(samples); i += 2 {
+			v := float64(samples[i])
+			if math.Abs(v) > 1 {
+				t.Fatalf("%s: sample %v out of range", n, v)
+			}
+			sum += v * v
+			if i >= 2 {
+				d := v - float64(samples[i-2])
+				diff += d * d
+			}
+		}
+		if sum == 0 {
+			t.Fatalf("%s: silent", n)
+		}
+		return diff / sum
+	}
+
+	white, pink, brown := roughness(NoiseWhite), roughness(NoisePink), roughness(NoiseBrown)
+	if !(white > pink && pink > brown) {
+		t.Errorf("Expected white > pink > brown roughness, got %.3f, %.3f, %.3f", white, pink, brown)
+	}
+	roughness(NoiseRain)
+}
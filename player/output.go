@@ -0,0 +1,239 @@
+package player
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/oto/v2"
+)
+
+// Names of the outputs OpenOutput knows.
+const (
+	OutputDevice = "device" // the sound card, through oto
+	OutputNull   = "null"   // nothing is heard; sounds still play in real time
+	OutputRecord = "record" // like null, but every play and stop is logged
+)
+
+// Outputs lists the names accepted by OpenOutput.
+var Outputs = []string{OutputDevice, OutputNull, OutputRecord}
+
+// Output is where a mixer sends its stream. Start is called once, the first
+// time something is played; from then on the output reads pcm, 16-bit
+// little-endian stereo at SampleRate, which never ends.
+type Output interface {
+	Start(pcm io.Reader) error
+}
+
+// recorder is implemented by outputs that want to know what the tracks do,
+// not only what they sound like.
+type recorder interface {
+	record(Event)
+}
+
+// OpenOutput returns the output called name; "" is the device. The record
+// output writes one line per event to log.
+func OpenOutput(name string, log io.Writer) (Output, error) {
+	switch name {
+	case "", OutputDevice:
+		return DeviceOutput(), nil
+	case OutputNull:
+		return NullOutput(), nil
+	case OutputRecord:
+		return &Recorder{realtime: true, log: log}, nil
+	}
+	return nil, fmt.Errorf("unknown audio output %q", name)
+}
+
+// SetOutput makes the default mixer play through o. It has no effect once
+// something has been played.
+func SetOutput(o Output) {
+	defaultMixer.SetOutput(o)
+}
+
+type deviceOutput struct {
+	engine *engine
+}
+
+// DeviceOutput plays through the sound card. It is the default.
+func DeviceOutput() Output {
+	return deviceOutput{engine: defaultEngine}
+}
+
+func (o deviceOutput) Start(pcm io.Reader) error {
+	ctx, err := o.engine.context()
+	if err != nil {
+		return err
+	}
+	out := ctx.NewPlayer(pcm)
+	if s, ok := out.(oto.BufferSizeSetter); ok {
+		s.SetBufferSize(int(outputBuffer.Seconds()*SampleRate) * channelCount * bitDepthInBytes)
+	}
+	out.Play()
+	return nil
+}
+
+type nullOutput struct{}
+
+// NullOutput throws the audio away. It still reads it as fast as a sound
+// card would, so positions advance and sounds finish on time.
+func NullOutput() Output {
+	return nullOutput{}
+}
+
+func (nullOutput) Start(pcm io.Reader) error {
+	go pace(pcm)
+	return nil
+}
+
+// paceInterval is how often a silent output reads.
+const paceInterval = 10 * time.Millisecond
+
+// pace reads pcm at the rate it would be played and throws it away.
+func pace(pcm io.Reader) {
+	const frameBytes = channelCount * bitDepthInBytes
+	buf := make([]byte, 0, 4*int(paceInterval.Seconds()*SampleRate)*frameBytes)
+	begin := time.Now()
+	var frames int64
+	ticker := time.NewTicker(paceInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		due := int64(time.Since(begin).Seconds()*SampleRate) - frames
+		for due > 0 {
+			n := min(due*frameBytes, int64(cap(buf)))
+			chunk := buf[:n]
+			if _, err := io.ReadFull(pcm, chunk); err != nil {
+				return
+			}
+			frames += n / frameBytes
+			due -= n / frameBytes
+		}
+	}
+}
+
+// EventKind says what happened on a track.
+type EventKind int
+
+const (
+	EventPlay   EventKind = iota // a sound started
+	EventStop                    // the sound was stopped
+	EventPause                   // the sound was paused
+	EventResume                  // the sound was resumed
+	EventFinish                  // the sound ended by itself
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPlay:
+		return "play"
+	case EventStop:
+		return "stop"
+	case EventPause:
+		return "pause"
+	case EventResume:
+		return "resume"
+	case EventFinish:
+		return "finish"
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// Event is something a track did, as seen by a Recorder.
+type Event struct {
+	At    time.Duration // how far into the output stream it happened
+	Track string
+	Kind  EventKind
+	Sound string // for EventPlay, the file or the kind of generated sound
+}
+
+func (e Event) String() string {
+	s := fmt.Sprintf("%v %s %v", e.At, e.Track, e.Kind)
+	if e.Sound != "" {
+		s += " " + e.Sound
+	}
+	return s
+}
+
+// errNotStarted is returned by Recorder.Advance before anything is played.
+var errNotStarted = errors.New("nothing has been played yet")
+
+// Recorder is an output that keeps what it is sent: the events of every
+// track and the audio itself. The audio is only read when Advance is called,
+// so tests decide how much time passes.
+//
+// The recorder returned by OpenOutput reads in real time instead, writes
+// each event to its log, and does not keep the audio.
+type Recorder struct {
+	realtime bool
+	log      io.Writer
+
+	mu     sync.Mutex
+	pcm    io.Reader
+	data   []byte
+	events []Event
+}
+
+// NewRecorder returns a recorder that is advanced by hand.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Start(pcm io.Reader) error {
+	r.mu.Lock()
+	r.pcm = pcm
+	r.mu.Unlock()
+	if r.realtime {
+		go pace(pcm)
+	}
+	return nil
+}
+
+// Advance reads d worth of audio and keeps it.
+func (r *Recorder) Advance(d time.Duration) error {
+	r.mu.Lock()
+	pcm := r.pcm
+	r.mu.Unlock()
+	if pcm == nil {
+		return errNotStarted
+	}
+	// Reading mixes the tracks, which may record events, so it is done
+	// without holding mu.
+	buf := make([]byte, int(d.Seconds()*SampleRate)*channelCount*bitDepthInBytes)
+	if _, err := io.ReadFull(pcm, buf); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data = append(r.data, buf...)
+	return nil
+}
+
+// Samples returns the audio read so far, interleaved left and right.
+func (r *Recorder) Samples() []int16 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	samples := make([]int16, len(r.data)/bitDepthInBytes)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(r.data[2*i:]))
+	}
+	return samples
+}
+
+// Events returns the events recorded so far, oldest first.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func (r *Recorder) record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.log != nil {
+		fmt.Fprintln(r.log, "audio:", e)
+	}
+	r.events = append(r.events, e)
+}
@@ -0,0 +1,57 @@
+package player
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	bell := filepath.Join(dir, "bell.wav")
+	writeWAV(t, bell, SampleRate/10, 1<<14)
+
+	rec := NewRecorder()
+	m := NewMixer()
+	m.SetOutput(rec)
+	cue := m.AddTrack(CueTrack, Cues)
+	cue.SetFade(0, 0)
+	if err := rec.Advance(time.Second); err == nil {
+		t.Error("Expected Advance to fail before anything is played")
+	}
+
+	if err := cue.Play(bell); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Advance(200 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := cue.Play(bell); err != nil {
+		t.Fatal(err)
+	}
+	cue.Stop()
+
+	expected := []Event{
+		{At: 0, Track: CueTrack, Kind: EventPlay, Sound: bell},
+		{At: 100 * time.Millisecond, Track: CueTrack, Kind: EventFinish},
+		{At: 200 * time.Millisecond, Track: CueTrack, Kind: EventPlay, Sound: bell},
+		{At: 200 * time.Millisecond, Track: CueTrack, Kind: EventStop},
+	}
+	events := rec.Events()
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Event %d: expected %v, got %v", i, expected[i], events[i])
+		}
+	}
+
+	samples := rec.Samples()
+	if len(samples) != SampleRate/5*2 {
+		t.Fatalf("Expected 200ms of stereo samples, got %d", len(samples))
+	}
+	if samples[0] == 0 || samples[len(samples)-1] != 0 {
+		t.Errorf("Expected the bell and then silence, got %d and %d", samples[0], samples[len(samples)-1])
+	}
+}
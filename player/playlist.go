@@ -0,0 +1,268 @@
+package player
+
+import (
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrEmptyPlaylist is returned when playing a playlist without any files.
+var ErrEmptyPlaylist = errors.New("no audio files in the playlist folder")
+
+// Playlist plays the audio files of a folder one after another on the
+// playlist track, crossfading between them.
+type Playlist struct {
+	track *Track
+
+	mu        sync.Mutex
+	files     []string
+	order     []int // indexes into files, shuffled or not
+	pos       int
+	shuffle   bool
+	loop      bool
+	crossfade time.Duration
+	playing   bool // started and not stopped; may be paused
+	gen       int  // incremented for every file started
+	onChange  func(file string)
+}
+
+// NewPlaylist returns an empty playlist on the playlist track of the default
+// mixer.
+func NewPlaylist() *Playlist {
+	return newPlaylist(defaultMixer.Track(PlaylistTrack))
+}
+
+func newPlaylist(track *Track) *Playlist {
+	return &Playlist{track: track, loop: true}
+}
+
+// Load replaces the files with the supported audio files in dir, in name
+// order, and stops playback.
+func (pl *Playlist) Load(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && slices.Contains(Extensions, strings.ToLower(filepath.Ext(e.Name()))) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	pl.Stop()
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.files = files
+	pl.order, pl.pos = nil, 0
+	return nil
+}
+
+// SetShuffle sets whether the files play in random order. It takes effect
+// the next time the playlist starts over.
+func (pl *Playlist) SetShuffle(shuffle bool) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.shuffle = shuffle
+}
+
+// SetLoop sets whether the playlist starts over after the last file.
+func (pl *Playlist) SetLoop(loop bool) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.loop = loop
+}
+
+// SetCrossfade sets how long each file overlaps the next one.
+func (pl *Playlist) SetCrossfade(d time.Duration) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.crossfade = d
+}
+
+// OnChange registers f to be called with the file that starts playing, or ""
+// when the playlist stops. f must not call back into the playlist.
+func (pl *Playlist) OnChange(f func(file string)) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.onChange = f
+}
+
+// Current returns the file being played, or "".
+func (pl *Playlist) Current() string {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	return pl.current()
+}
+
+func (pl *Playlist) current() string {
+	if !pl.playing {
+		return ""
+	}
+	return pl.files[pl.order[pl.pos]]
+}
+
+// Play starts the playlist, or resumes it if it is paused.
+func (pl *Playlist) Play() error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if pl.playing {
+		pl.track.Resume()
+		return nil
+	}
+	if len(pl.files) == 0 {
+		return ErrEmptyPlaylist
+	}
+	if pl.order == nil || pl.pos >= len(pl.order) {
+		pl.newOrder()
+	}
+	return pl.start()
+}
+
+// Pause fades the playlist out, keeping its place for Play.
+func (pl *Playlist) Pause() {
+	pl.track.Pause()
+}
+
+// Stop ends playback. The next Play starts from the first file.
+func (pl *Playlist) Stop() {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if !pl.playing {
+		return
+	}
+	pl.playing = false
+	pl.gen++
+	pl.order, pl.pos = nil, 0
+	pl.track.Stop()
+	pl.changed()
+}
+
+func (pl *Playlist) newOrder() {
+	pl.order = make([]int, len(pl.files))
+	for i := range pl.order {
+		pl.order[i] = i
+	}
+	if pl.shuffle {
+		rand.Shuffle(len(pl.order), func(i, j int) {
+			pl.order[i], pl.order[j] = pl.order[j], pl.order[i]
+		})
+	}
+	pl.pos = 0
+}
+
+// start plays the file at pos, skipping files that cannot be played.
+func (pl *Playlist) start() error {
+	var firstErr error
+	for tried := 0; tried < len(pl.order); tried++ {
+		file := pl.files[pl.order[pl.pos]]
+		src, closer, err := openFile(file)
+		if err == nil {
+			pl.gen++
+			gen := pl.gen
+			ahead := newReadAhead(src, pl.crossfade, func() {
+				// Called from the mixer; advance on another goroutine.
+				go pl.next(gen)
+			})
+			pl.track.SetFade(pl.crossfade, pl.crossfade)
+			if err := pl.track.playSource(file, ahead, closer); err != nil {
+				return err
+			}
+			pl.playing = true
+			pl.changed()
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if !pl.advance() {
+			break
+		}
+	}
+	pl.playing = false
+	pl.changed()
+	return firstErr
+}
+
+// advance moves pos to the next file, reporting false at the end of a
+// playlist that does not loop.
+func (pl *Playlist) advance() bool {
+	pl.pos++
+	if pl.pos < len(pl.order) {
+		return true
+	}
+	if !pl.loop {
+		return false
+	}
+	pl.newOrder()
+	return true
+}
+
+// next starts the file after the one started as gen, once that one is
+// about to end.
+func (pl *Playlist) next(gen int) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if gen != pl.gen || !pl.playing {
+		return
+	}
+	if !pl.advance() {
+		pl.playing = false
+		pl.order, pl.pos = nil, 0
+		pl.changed()
+		return
+	}
+	pl.start()
+}
+
+func (pl *Playlist) changed() {
+	if pl.onChange != nil {
+		pl.onChange(pl.current())
+	}
+}
+
+// readAhead reads a source some time ahead of playback so that ending is
+// called that long before the sound is over, in time to crossfade.
+type readAhead struct {
+	src    source
+	size   int // samples to keep buffered
+	buf    []float32
+	tmp    []float32
+	err    error
+	ending func()
+}
+
+func newReadAhead(src source, d time.Duration, ending func()) *readAhead {
+	return &readAhead{
+		src:    src,
+		size:   int(d.Seconds()*SampleRate) * 2,
+		tmp:    make([]float32, 4096),
+		ending: ending,
+	}
+}
+
+func (r *readAhead) length() int64 { return sourceLength(r.src) }
+
+func (r *readAhead) Read(samples []float32) (int, error) {
+	for r.err == nil && len(r.buf) < r.size+len(samples) {
+		n, err := fill(r.src, r.tmp)
+		r.buf = append(r.buf, r.tmp[:n]...)
+		if err != nil {
+			r.err = err
+			r.ending()
+		} else if n == 0 {
+			break
+		}
+	}
+	n := copy(samples, r.buf)
+	r.buf = r.buf[n:]
+	if n == 0 && r.err != nil {
+		return 0, r.err
+	}
+	return n, nil
+}
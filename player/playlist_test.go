@@ -0,0 +1,83 @@
+package player
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlaylistPlaysFolderInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeWAV(t, filepath.Join(dir, "a.wav"), SampleRate/5, 1<<13)
+	writeWAV(t, filepath.Join(dir, "b.wav"), SampleRate/5, 1<<14)
+	writeWAV(t, filepath.Join(dir, "notes.txt"), 10, 0)
+
+	m := NewMixer()
+	m.startOnce.Do(func() {}) // no audio device in tests
+	pl := newPlaylist(m.AddTrack("playlist", Ambience))
+	pl.SetLoop(false)
+	pl.SetCrossfade(50 * time.Millisecond)
+	changes := make(chan string, 10)
+	pl.OnChange(func(file string) { changes <- file })
+
+	if err := pl.Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := pl.Play(); err != nil {
+		t.Fatal(err)
+	}
+
+	var played []string
+	buf := make([]float32, SampleRate/100*2)
+	deadline := time.After(5 * time.Second)
+	for len(played) < 3 {
+		select {
+		case file := <-changes:
+			played = append(played, file)
+		case <-deadline:
+			t.Fatalf("Expected a.wav, b.wav and then the end, got %q", played)
+		default:
+			m.Read(buf)
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if filepath.Base(played[0]) != "a.wav" || filepath.Base(played[1]) != "b.wav" || played[2] != "" {
+		t.Errorf("Expected a.wav, b.wav and then the end, got %q", played)
+	}
+	if pl.Current() != "" {
+		t.Errorf("Expected nothing playing after the last file, got %q", pl.Current())
+	}
+}
+
+func TestReadAheadWarnsBeforeEnd(t *testing.T) {
+	const total, ahead = 1000, 100
+	left := total
+	src := sourceFunc(func(samples []float32) (int, error) {
+		n := min(len(samples), left)
+		left -= n
+		if left == 0 {
+			return n, io.EOF
+		}
+		return n, nil
+	})
+	returned := 0
+	warnedAt := -1
+	r := newReadAhead(src, 0, func() { warnedAt = returned })
+	r.size = ahead
+
+	buf := make([]float32, 10)
+	for {
+		n, err := r.Read(buf)
+		returned += n
+		if err != nil {
+			break
+		}
+	}
+	if returned != total {
+		t.Errorf("Expected all %d samples, got %d", total, returned)
+	}
+	if warnedAt < 0 || total-warnedAt < ahead {
+		t.Errorf("Expected the warning at least %d samples before the end, got it after %d", ahead, warnedAt)
+	}
+}
@@ -0,0 +1,79 @@
+package player
+
+import "io"
+
+// resampler converts a stereo source from its own sample rate to SampleRate
+// using linear interpolation, which is plenty for notification sounds and
+// ambience.
+type resampler struct {
+	src  source
+	step float64 // input frames per output frame
+	pos  float64 // position between prev (0) and next (1)
+
+	prev, next [2]float32
+
+	in      []float32
+	inPos   int
+	inLen   int
+	pending error // error returned by src together with its last samples
+	err     error
+}
+
+// resample returns src converted from rate to SampleRate.
+func resample(src source, rate int) source {
+	if rate == SampleRate || rate <= 0 {
+		return src
+	}
+	r := &resampler{
+		src:  src,
+		step: float64(rate) / SampleRate,
+		pos:  2, // load the first two frames before producing output
+		in:   make([]float32, 4096),
+	}
+	return withLength(r, sourceLength(src)*SampleRate/int64(rate))
+}
+
+func (r *resampler) Read(out []float32) (int, error) {
+	n := 0
+	for n+1 < len(out) {
+		for r.pos >= 1 {
+			if !r.advance() {
+				if n > 0 {
+					return n, nil
+				}
+				return 0, r.err
+			}
+			r.pos--
+		}
+		t := float32(r.pos)
+		out[n] = r.prev[0] + (r.next[0]-r.prev[0])*t
+		out[n+1] = r.prev[1] + (r.next[1]-r.prev[1])*t
+		n += 2
+		r.pos += r.step
+	}
+	return n, nil
+}
+
+// advance moves one input frame forward, refilling the buffer as needed.
+func (r *resampler) advance() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.inPos+1 >= r.inLen {
+		if r.pending != nil {
+			r.err = r.pending
+			return false
+		}
+		n, err := r.src.Read(r.in)
+		r.inPos, r.inLen = 0, n
+		if err != nil {
+			r.pending = err
+		} else if n == 0 {
+			r.pending = io.ErrNoProgress
+		}
+	}
+	r.prev = r.next
+	r.next = [2]float32{r.in[r.inPos], r.in[r.inPos+1]}
+	r.inPos += 2
+	return true
+}
@@ -0,0 +1,131 @@
+package player
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// source produces interleaved stereo float32 samples in [-1, 1]. Read fills
+// samples (whose length is even) and returns the number of samples written,
+// returning io.EOF once the sound is over.
+type source interface {
+	Read(samples []float32) (int, error)
+}
+
+// stereo converts interleaved samples with any number of channels to stereo.
+// Mono is duplicated to both sides; extra channels beyond the first two are
+// dropped.
+type stereo struct {
+	r        func([]float32) (int, error)
+	channels int
+	buf      []float32
+}
+
+func newStereo(read func([]float32) (int, error), channels int) source {
+	if channels == 2 {
+		return sourceFunc(read)
+	}
+	return &stereo{r: read, channels: channels}
+}
+
+func (s *stereo) Read(samples []float32) (int, error) {
+	frames := len(samples) / 2
+	if cap(s.buf) < frames*s.channels {
+		s.buf = make([]float32, frames*s.channels)
+	}
+	buf := s.buf[:frames*s.channels]
+	n, err := s.r(buf)
+	n /= s.channels
+	for i := 0; i < n; i++ {
+		left := buf[i*s.channels]
+		right := left
+		if s.channels > 1 {
+			right = buf[i*s.channels+1]
+		}
+		samples[2*i], samples[2*i+1] = left, right
+	}
+	return 2 * n, err
+}
+
+// lengther is implemented by sources that know how many frames they hold.
+type lengther interface {
+	length() int64
+}
+
+// sized gives a source a known length.
+type sized struct {
+	source
+	frames int64
+}
+
+func (s sized) length() int64 { return s.frames }
+
+// withLength returns src with a length of frames, or src itself when the
+// length is unknown (0 or less).
+func withLength(src source, frames int64) source {
+	if frames <= 0 {
+		return src
+	}
+	return sized{src, frames}
+}
+
+// sourceLength returns the length of src in frames, or 0 if unknown.
+func sourceLength(src source) int64 {
+	if l, ok := src.(lengther); ok {
+		return l.length()
+	}
+	return 0
+}
+
+type sourceFunc func([]float32) (int, error)
+
+func (f sourceFunc) Read(samples []float32) (int, error) { return f(samples) }
+
+// pcmReader turns a source into the 16-bit little-endian stereo stream that
+// the audio engine plays.
+type pcmReader struct {
+	src source
+	buf []float32
+	err error
+}
+
+func newPCMReader(src source) io.Reader {
+	return &pcmReader{src: src}
+}
+
+func (p *pcmReader) Read(out []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	// Whole stereo frames only: 2 channels * 2 bytes.
+	samples := len(out) / bitDepthInBytes / channelCount * channelCount
+	if samples == 0 {
+		return 0, nil
+	}
+	if cap(p.buf) < samples {
+		p.buf = make([]float32, samples)
+	}
+	buf := p.buf[:samples]
+
+	n, err := p.src.Read(buf)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(floatToInt16(buf[i])))
+	}
+	if err != nil {
+		p.err = err
+		if n > 0 {
+			err = nil
+		}
+	}
+	return 2 * n, err
+}
+
+func floatToInt16(v float32) int16 {
+	if v > 1 {
+		v = 1
+	} else if v < -1 {
+		v = -1
+	}
+	return int16(math.Round(float64(v) * math.MaxInt16))
+}
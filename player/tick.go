@@ -0,0 +1,69 @@
+package player
+
+import (
+	"io"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// A tick is a short burst of noise through a decaying resonance, like the
+// escapement of a mechanical timer. The tock is pitched lower.
+const (
+	tickLength = 40 * time.Millisecond
+	tickPitch  = 3200.0 // Hz
+	tockPitch  = 2100.0
+	tickDecay  = 0.9985 // per frame
+)
+
+type tickSource struct {
+	rng   *rand.Rand
+	phase float64
+	step  float64 // phase increment per frame
+	env   float64
+	left  int // frames
+}
+
+func newTickSource(tock bool, seed uint64) source {
+	pitch := tickPitch
+	if tock {
+		pitch = tockPitch
+	}
+	return &tickSource{
+		rng:  rand.New(rand.NewPCG(seed, 3)),
+		step: 2 * math.Pi * pitch / SampleRate,
+		env:  1,
+		left: int(tickLength.Seconds() * SampleRate),
+	}
+}
+
+func (s *tickSource) Read(samples []float32) (int, error) {
+	n := 0
+	for ; n+1 < len(samples) && s.left > 0; n += 2 {
+		// The noise click dies out within a few milliseconds; the ring lasts.
+		click := (s.rng.Float64()*2 - 1) * s.env * s.env * s.env * s.env
+		ring := math.Sin(s.phase) * s.env
+		v := float32(0.5*ring + 0.3*click)
+		samples[n], samples[n+1] = v, v
+		s.phase = math.Mod(s.phase+s.step, 2*math.Pi)
+		s.env *= tickDecay
+		s.left--
+	}
+	if s.left == 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Tick plays one tick of a clock on the tick track of the default mixer; tock
+// selects the lower second half of a tick-tock.
+func Tick(tock bool) error {
+	return defaultMixer.Track(TickTrack).playSource(tickName(tock), newTickSource(tock, rand.Uint64()), nil)
+}
+
+func tickName(tock bool) string {
+	if tock {
+		return "tock"
+	}
+	return "tick"
+}
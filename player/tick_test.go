@@ -0,0 +1,25 @@
+package player
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTickSource(t *testing.T) {
+	for _, tock := range []bool{false, true} {
+		samples := readAll(t, newTickSource(tock, 1))
+		if got, want := len(samples)/2, int(tickLength.Seconds()*SampleRate); got != want {
+			t.Errorf("tock=%v: expected %d frames, got %d", tock, want, got)
+		}
+		var peak, tail float64
+		for i, v := range samples {
+			peak = math.Max(peak, math.Abs(float64(v)))
+			if i >= len(samples)-200 {
+				tail = math.Max(tail, math.Abs(float64(v)))
+			}
+		}
+		if peak < 0.3 || peak > 1 || tail > peak/10 {
+			t.Errorf("tock=%v: expected a sharp tick that dies out, got peak %v and tail %v", tock, peak, tail)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package player
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Fade lengths for cues and for everything else, unless SetFade is called.
+// Cues start almost at once so the attack of a bell is kept; long sounds
+// swell in and out.
+const (
+	cueFadeIn  = 20 * time.Millisecond
+	cueFadeOut = 300 * time.Millisecond
+	longFade   = 2 * time.Second
+)
+
+// Track is one named channel of a Mixer. It plays one sound at a time:
+// starting a new sound crossfades from the previous one.
+type Track struct {
+	name     string
+	category Category
+	mixer    *Mixer
+
+	mu      sync.Mutex
+	fadeIn  time.Duration
+	fadeOut time.Duration
+	loop    bool
+	paused  bool
+	volume  float32  // relative to the category volume
+	voices  []*voice // the current sound last, preceded by any still fading out
+	duck    float32  // ducking gain, only used by the mixer
+
+	onFinished func()
+}
+
+// voice is one sound being played on a track.
+type voice struct {
+	fader    *fader
+	closer   io.Closer // nil for generated sounds
+	stopping bool
+	played   int64 // frames mixed so far
+	length   int64 // total frames, 0 if unknown
+}
+
+func (v *voice) close() {
+	if v.closer != nil {
+		v.closer.Close()
+	}
+}
+
+func newTrack(m *Mixer, name string, c Category) *Track {
+	t := &Track{name: name, category: c, mixer: m, fadeIn: longFade, fadeOut: longFade, volume: 1, duck: 1}
+	if c == Cues {
+		t.fadeIn, t.fadeOut = cueFadeIn, cueFadeOut
+	}
+	return t
+}
+
+// Name returns the name the track was added with.
+func (t *Track) Name() string {
+	return t.name
+}
+
+// SetFade sets how long sounds take to fade in when started and to fade out
+// when stopped or replaced.
+func (t *Track) SetFade(in, out time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.fadeIn, t.fadeOut = in, out
+}
+
+// SetVolume sets the volume of the track, from 0 to 1, on top of its
+// category volume.
+func (t *Track) SetVolume(v float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.volume = float32(clampVolume(v))
+}
+
+// SetLoop sets whether files played from now on start over when they end.
+func (t *Track) SetLoop(loop bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loop = loop
+}
+
+// Play starts playing file, replacing whatever the track was playing.
+func (t *Track) Play(file string) error {
+	if err := t.mixer.start(); err != nil {
+		return err
+	}
+	return t.play(file)
+}
+
+func (t *Track) play(file string) error {
+	t.mu.Lock()
+	loop := t.loop
+	t.mu.Unlock()
+
+	var (
+		src    source
+		closer io.Closer
+		err    error
+	)
+	if loop {
+		var l *looper
+		l, err = newLooper(file)
+		src, closer = l, l
+	} else if cached, ok := t.mixer.cache.source(file); ok {
+		src = cached
+	} else {
+		src, closer, err = openFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start(file, src, closer)
+	return nil
+}
+
+// playSource plays src, replacing whatever the track was playing. name says
+// what src is to a Recorder. closer, if not nil, is closed once src is no
+// longer played.
+func (t *Track) playSource(name string, src source, closer io.Closer) error {
+	if err := t.mixer.start(); err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start(name, src, closer)
+	return nil
+}
+
+func (t *Track) start(name string, src source, closer io.Closer) {
+	t.stop()
+	t.voices = append(t.voices, &voice{fader: newFader(src, t.category, t.fadeIn), closer: closer, length: sourceLength(src)})
+	t.mixer.record(t.name, EventPlay, name)
+}
+
+// Stop fades the current sound out.
+func (t *Track) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.currentVoice() != nil {
+		t.mixer.record(t.name, EventStop, "")
+	}
+	t.stop()
+}
+
+func (t *Track) stop() {
+	t.paused = false
+	for _, v := range t.voices {
+		if !v.stopping {
+			v.stopping = true
+			v.fader.fadeOut(t.fadeOut, nil)
+		}
+	}
+}
+
+// Pause fades the current sound out and holds it where it is until Resume.
+func (t *Track) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n := len(t.voices); n > 0 && !t.paused {
+		t.paused = true
+		t.voices[n-1].fader.fadeTo(0, t.fadeOut)
+		t.mixer.record(t.name, EventPause, "")
+	}
+}
+
+// Resume fades a paused sound back in from where it was paused.
+func (t *Track) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n := len(t.voices); n > 0 && t.paused {
+		t.paused = false
+		t.voices[n-1].fader.fadeTo(1, t.fadeIn)
+		t.mixer.record(t.name, EventResume, "")
+	}
+}
+
+// IsPlaying reports whether the track has a sound that is neither paused nor
+// being stopped.
+func (t *Track) IsPlaying() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.currentVoice() != nil && !t.paused
+}
+
+// Position returns how much of the current sound has been played.
+func (t *Track) Position() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v := t.currentVoice(); v != nil {
+		return framesToDuration(v.played)
+	}
+	return 0
+}
+
+// Duration returns the length of the current sound, or 0 if it has none or
+// its length is unknown.
+func (t *Track) Duration() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v := t.currentVoice(); v != nil {
+		return framesToDuration(v.length)
+	}
+	return 0
+}
+
+// OnFinished registers f to be called when a sound ends by itself, not when
+// it is stopped or replaced. f runs on its own goroutine.
+func (t *Track) OnFinished(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onFinished = f
+}
+
+// currentVoice returns the sound started last, unless it is being stopped.
+func (t *Track) currentVoice() *voice {
+	if n := len(t.voices); n > 0 && !t.voices[n-1].stopping {
+		return t.voices[n-1]
+	}
+	return nil
+}
+
+func framesToDuration(frames int64) time.Duration {
+	return time.Duration(frames) * time.Second / SampleRate
+}
+
+// fadeTo ramps the current sound to level, a fraction of its full volume,
+// over d.
+func (t *Track) fadeTo(level float64, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if v := t.currentVoice(); v != nil {
+		v.fader.fadeTo(float32(level), d)
+	}
+}
+
+// mix adds the track's next samples to out, ramping the ducking gain
+// towards duck. trackBuf and voiceBuf are scratch space as long as out.
+func (t *Track) mix(out, trackBuf, voiceBuf []float32, duck float32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.voices) == 0 {
+		t.duck = duck
+		return
+	}
+
+	clear(trackBuf)
+	live := t.voices[:0]
+	for _, v := range t.voices {
+		if t.paused && !v.stopping && v.fader.silent() {
+			// Paused: keep the position instead of reading on in silence.
+			live = append(live, v)
+			continue
+		}
+		n, err := fill(v.fader, voiceBuf)
+		for i := 0; i < n; i++ {
+			trackBuf[i] += voiceBuf[i]
+		}
+		v.played += int64(n / 2)
+		if err != nil {
+			v.close()
+			if !v.stopping {
+				t.mixer.recordAt(int64(n/2), t.name, EventFinish, "")
+				if t.onFinished != nil {
+					go t.onFinished()
+				}
+			}
+			continue
+		}
+		live = append(live, v)
+	}
+	clear(t.voices[len(live):])
+	t.voices = live
+
+	step := rampStep(duckRelease)
+	if duck < t.duck {
+		step = rampStep(duckAttack)
+	}
+	for i := 0; i+1 < len(out); i += 2 {
+		switch {
+		case t.duck < duck:
+			t.duck = min(t.duck+step, duck)
+		case t.duck > duck:
+			t.duck = max(t.duck-step, duck)
+		}
+		g := t.duck * t.volume
+		out[i] += trackBuf[i] * g
+		out[i+1] += trackBuf[i+1] * g
+	}
+}
+
+// fill reads from src until buf is full or src fails. A source that has
+// nothing right now leaves the rest of buf silent.
+func fill(src source, buf []float32) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := src.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		if m == 0 {
+			break
+		}
+	}
+	return n, nil
+}
+
+// openFile opens and decodes an audio file.
+func openFile(file string) (source, io.Closer, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening audio file: %w", err)
+	}
+	src, err := decode(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return src, f, nil
+}
+
+// looper plays a file over and over, reopening it each time it ends.
+type looper struct {
+	file string
+	src  source
+	f    io.Closer
+}
+
+func newLooper(file string) (*looper, error) {
+	src, f, err := openFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &looper{file: file, src: src, f: f}, nil
+}
+
+func (l *looper) Read(samples []float32) (int, error) {
+	n, err := l.src.Read(samples)
+	if err != io.EOF {
+		return n, err
+	}
+	l.f.Close()
+	l.src, l.f, err = openFile(l.file)
+	if err != nil {
+		l.src, l.f = sourceFunc(func([]float32) (int, error) { return 0, io.EOF }), io.NopCloser(nil)
+		return n, err
+	}
+	if n > 0 {
+		return n, nil
+	}
+	// Read once from the new copy; a file without any audio ends the loop.
+	return l.src.Read(samples)
+}
+
+// length is the length of one pass through the file.
+func (l *looper) length() int64 { return sourceLength(l.src) }
+
+func (l *looper) Close() error {
+	return l.f.Close()
+}
+
+// Play plays file on the cue track of the default mixer.
+func Play(file string) error {
+	return defaultMixer.Track(CueTrack).Play(file)
+}
+
+// Stop stops the cue track of the default mixer.
+func Stop() {
+	defaultMixer.Track(CueTrack).Stop()
+}
+
+// IsPlaying reports whether a cue is playing.
+func IsPlaying() bool {
+	return defaultMixer.Track(CueTrack).IsPlaying()
+}
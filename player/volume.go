@@ -0,0 +1,59 @@
+package player
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Category groups sounds that share a volume setting.
+type Category int
+
+const (
+	Cues Category = iota
+	Ambience
+	Meditation
+	categoryCount
+)
+
+var (
+	masterVolume    atomic.Uint64 // float64 bits
+	categoryVolumes [categoryCount]atomic.Uint64
+)
+
+func init() {
+	SetMasterVolume(1)
+	for c := Category(0); c < categoryCount; c++ {
+		SetVolume(c, 1)
+	}
+}
+
+// SetMasterVolume sets the volume applied to every sound, from 0 to 1.
+// Sounds that are already playing follow the change.
+func SetMasterVolume(v float64) {
+	masterVolume.Store(math.Float64bits(clampVolume(v)))
+}
+
+// SetVolume sets the volume of one category, from 0 to 1.
+func SetVolume(c Category, v float64) {
+	if c < 0 || c >= categoryCount {
+		return
+	}
+	categoryVolumes[c].Store(math.Float64bits(clampVolume(v)))
+}
+
+// volume is the effective gain for sounds of category c.
+func volume(c Category) float32 {
+	master := math.Float64frombits(masterVolume.Load())
+	category := math.Float64frombits(categoryVolumes[c].Load())
+	return float32(master * category)
+}
+
+func clampVolume(v float64) float64 {
+	if math.IsNaN(v) || v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
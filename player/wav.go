@@ -0,0 +1,148 @@
+package player
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// wavDecoder reads uncompressed PCM (8, 16, 24 or 32-bit) and IEEE float
+// (32 or 64-bit) WAV data.
+type wavDecoder struct {
+	r          *bufio.Reader
+	channels   int
+	rate       int
+	format     int
+	bytesPer   int // bytes per sample
+	remaining  int64
+	frameBytes []byte
+}
+
+// decodeWAV parses the RIFF header of r and returns a stereo source and its
+// sample rate.
+func decodeWAV(r io.Reader) (source, int, error) {
+	br := bufio.NewReader(r)
+	var header [12]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, 0, fmt.Errorf("wav: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, 0, errors.New("wav: not a RIFF/WAVE file")
+	}
+
+	d := &wavDecoder{r: br}
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(br, chunk[:]); err != nil {
+			return nil, 0, fmt.Errorf("wav: missing data chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, errors.New("wav: short fmt chunk")
+			}
+			fmtChunk := make([]byte, size)
+			if _, err := io.ReadFull(br, fmtChunk); err != nil {
+				return nil, 0, fmt.Errorf("wav: %w", err)
+			}
+			d.format = int(binary.LittleEndian.Uint16(fmtChunk[0:2]))
+			d.channels = int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+			d.rate = int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+			bits := int(binary.LittleEndian.Uint16(fmtChunk[14:16]))
+			if d.format == wavFormatExtensible && size >= 26 {
+				// The real format is the first two bytes of the sub-format GUID.
+				d.format = int(binary.LittleEndian.Uint16(fmtChunk[24:26]))
+			}
+			d.bytesPer = (bits + 7) / 8
+			haveFormat = true
+			if size%2 == 1 {
+				br.Discard(1)
+			}
+
+		case "data":
+			if !haveFormat {
+				return nil, 0, errors.New("wav: data chunk before fmt chunk")
+			}
+			if err := d.validate(); err != nil {
+				return nil, 0, err
+			}
+			d.remaining = size
+			d.frameBytes = make([]byte, d.bytesPer*d.channels)
+			return withLength(newStereo(d.read, d.channels), size/int64(len(d.frameBytes))), d.rate, nil
+
+		default:
+			// Skip LIST, fact, cue and other chunks; chunks are word aligned.
+			if _, err := br.Discard(int(size + size%2)); err != nil {
+				return nil, 0, fmt.Errorf("wav: %w", err)
+			}
+		}
+	}
+}
+
+func (d *wavDecoder) validate() error {
+	if d.channels < 1 || d.rate <= 0 {
+		return fmt.Errorf("wav: invalid format (%d channels, %d Hz)", d.channels, d.rate)
+	}
+	switch {
+	case d.format == wavFormatPCM && d.bytesPer >= 1 && d.bytesPer <= 4:
+	case d.format == wavFormatFloat && (d.bytesPer == 4 || d.bytesPer == 8):
+	default:
+		return fmt.Errorf("wav: unsupported encoding (format %d, %d-bit)", d.format, d.bytesPer*8)
+	}
+	return nil
+}
+
+// read fills samples with interleaved samples in the file's channel layout.
+func (d *wavDecoder) read(samples []float32) (int, error) {
+	n := 0
+	for n+d.channels <= len(samples) {
+		if d.remaining < int64(len(d.frameBytes)) {
+			return n, io.EOF
+		}
+		if _, err := io.ReadFull(d.r, d.frameBytes); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF // tolerate a truncated last frame
+			}
+			return n, err
+		}
+		d.remaining -= int64(len(d.frameBytes))
+		for c := 0; c < d.channels; c++ {
+			samples[n+c] = d.sample(d.frameBytes[c*d.bytesPer : (c+1)*d.bytesPer])
+		}
+		n += d.channels
+	}
+	return n, nil
+}
+
+func (d *wavDecoder) sample(b []byte) float32 {
+	if d.format == wavFormatFloat {
+		if d.bytesPer == 8 {
+			return float32(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	switch d.bytesPer {
+	case 1:
+		return (float32(b[0]) - 128) / 128 // 8-bit WAV is unsigned
+	case 2:
+		return float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+	case 3:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float32(v) / (1 << 23)
+	default:
+		return float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+	}
+}
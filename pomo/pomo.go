@@ -1,6 +1,7 @@
 package pomo
 
 import (
+	"sync"
 	"time"
 
 	"pomodoro-do-ben/config"
@@ -14,6 +15,26 @@ const (
 	LongBreakState
 )
 
+// EventKind says what happened to a Timer.
+type EventKind int
+
+const (
+	EventStarted      EventKind = iota // Start was called
+	EventStopped                       // Stop was called
+	EventReset                         // Reset was called
+	EventTick                          // one second of a running phase elapsed
+	EventPhaseChanged                  // NextState moved to a new phase
+)
+
+// Event is passed to the listeners of a Timer. State, Duration and Remaining
+// are the timer's values right after the event.
+type Event struct {
+	Kind      EventKind
+	State     State
+	Duration  time.Duration // length of the current phase
+	Remaining time.Duration
+}
+
 type Timer struct {
 	State         State
 	Duration      time.Duration
@@ -23,6 +44,11 @@ type Timer struct {
 	config        *config.Config
 	Updates       chan struct{}
 	pomodoroCount int // Tracks completed pomodoros
+	previous      State
+	previousCount int // pomodoroCount before the last NextState
+
+	listenersMu sync.Mutex
+	listeners   []func(Event)
 }
 
 func NewTimer(cfg *config.Config) *Timer {
@@ -36,18 +62,46 @@ func NewTimer(cfg *config.Config) *Timer {
 	}
 }
 
+// OnEvent registers f to be called on every event. Tick events are sent from
+// the timer's goroutine just before Updates, so listeners stay in step with
+// the display; f must return quickly.
+func (t *Timer) OnEvent(f func(Event)) {
+	t.listenersMu.Lock()
+	defer t.listenersMu.Unlock()
+	t.listeners = append(t.listeners, f)
+}
+
+func (t *Timer) emit(kind EventKind) {
+	t.listenersMu.Lock()
+	listeners := t.listeners
+	t.listenersMu.Unlock()
+	e := Event{Kind: kind, State: t.State, Duration: t.Duration, Remaining: t.RemainingTime}
+	for _, f := range listeners {
+		f(e)
+	}
+}
+
 func (t *Timer) Start() {
 	t.IsRunning = true
 	t.ticker = time.NewTicker(time.Second)
 	go func() {
 		for range t.ticker.C {
-			t.Tick()
+			if t.IsRunning {
+				t.Tick()
+				t.emit(EventTick)
+			}
 			t.Updates <- struct{}{}
 		}
 	}()
+	t.emit(EventStarted)
 }
 
 func (t *Timer) Stop() {
+	t.stop()
+	t.emit(EventStopped)
+}
+
+func (t *Timer) stop() {
 	t.IsRunning = false
 	if t.ticker != nil {
 		t.ticker.Stop()
@@ -55,7 +109,12 @@ func (t *Timer) Stop() {
 }
 
 func (t *Timer) Reset() {
-	t.Stop()
+	t.reset()
+	t.emit(EventReset)
+}
+
+func (t *Timer) reset() {
+	t.stop()
 	t.RemainingTime = t.Duration
 }
 
@@ -69,11 +128,31 @@ func (t *Timer) Ticker() *time.Ticker {
 	return t.ticker
 }
 
+// Next returns the phase NextState would move to.
+func (t *Timer) Next() State {
+	switch t.State {
+	case Pomodoro:
+		if t.config.LongBreakInterval > 0 && (t.pomodoroCount+1)%t.config.LongBreakInterval == 0 {
+			return LongBreakState
+		}
+		return ShortBreakState
+	default:
+		return Pomodoro
+	}
+}
+
+// Completed returns how many pomodoros were completed since the last long
+// break.
+func (t *Timer) Completed() int {
+	return t.pomodoroCount
+}
+
 func (t *Timer) NextState() {
+	t.previous, t.previousCount = t.State, t.pomodoroCount
 	switch t.State {
 	case Pomodoro:
 		t.pomodoroCount++ // Increment pomodoro count after a completed Pomodoro
-		if t.pomodoroCount%t.config.LongBreakInterval == 0 {
+		if t.config.LongBreakInterval > 0 && t.pomodoroCount%t.config.LongBreakInterval == 0 {
 			t.State = LongBreakState
 			t.Duration = t.config.LongBreakDuration
 		} else {
@@ -88,5 +167,16 @@ func (t *Timer) NextState() {
 		t.Duration = t.config.FocusDuration
 		t.pomodoroCount = 0 // Reset pomodoro count after a long break
 	}
-	t.Reset()
+	t.reset()
+	t.emit(EventPhaseChanged)
+}
+
+// Extend goes back to the phase NextState last left and runs it for d more,
+// as if it had not ended.
+func (t *Timer) Extend(d time.Duration) {
+	t.stop()
+	t.State, t.pomodoroCount = t.previous, t.previousCount
+	t.Duration, t.RemainingTime = d, d
+	t.emit(EventPhaseChanged)
+	t.Start()
 }
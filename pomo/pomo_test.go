@@ -125,3 +125,83 @@ func TestNextState(t *testing.T) {
 		})
 	}
 }
+
+func TestEvents(t *testing.T) {
+	cfg := &config.Config{
+		FocusDuration:      time.Second * 3,
+		ShortBreakDuration: time.Minute * 5,
+		LongBreakInterval:  4,
+	}
+	timer := NewTimer(cfg)
+
+	events := make(chan Event, 10)
+	timer.OnEvent(func(e Event) { events <- e })
+
+	timer.Start()
+	if e := <-events; e.Kind != EventStarted {
+		t.Errorf("Expected EventStarted, got %v", e.Kind)
+	}
+	select {
+	case e := <-events:
+		if e.Kind != EventTick || e.Duration != cfg.FocusDuration || e.Remaining != time.Second*2 {
+			t.Errorf("Expected a tick with 2s remaining, got %+v", e)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("Expected a tick event")
+	}
+	timer.Stop()
+	if e := <-events; e.Kind != EventStopped {
+		t.Errorf("Expected EventStopped, got %v", e.Kind)
+	}
+
+	timer.NextState()
+	if e := <-events; e.Kind != EventPhaseChanged || e.State != ShortBreakState || e.Remaining != cfg.ShortBreakDuration {
+		t.Errorf("Expected a change to a short break, got %+v", e)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("Expected NextState to send a single event, also got %+v", e)
+	default:
+	}
+}
+
+func TestExtend(t *testing.T) {
+	cfg := &config.Config{
+		FocusDuration:      time.Minute * 25,
+		ShortBreakDuration: time.Minute * 5,
+		LongBreakDuration:  time.Minute * 15,
+		LongBreakInterval:  2,
+	}
+	timer := NewTimer(cfg)
+	timer.NextState()
+	timer.Extend(time.Minute * 5)
+	defer timer.Stop()
+
+	if timer.State != Pomodoro || !timer.IsRunning {
+		t.Errorf("Expected a running Pomodoro, got %v running %v", timer.State, timer.IsRunning)
+	}
+	if timer.Duration != time.Minute*5 || timer.RemainingTime != time.Minute*5 {
+		t.Errorf("Expected 5 more minutes, got %v of %v", timer.RemainingTime, timer.Duration)
+	}
+
+	// The extended pomodoro is still the first one, so a short break follows.
+	timer.NextState()
+	if timer.State != ShortBreakState {
+		t.Errorf("Expected a short break after the extension, got %v", timer.State)
+	}
+}
+
+func TestNext(t *testing.T) {
+	cfg := &config.Config{LongBreakInterval: 2}
+	timer := NewTimer(cfg)
+	for _, expected := range []State{ShortBreakState, Pomodoro, LongBreakState, Pomodoro, ShortBreakState} {
+		next := timer.Next()
+		timer.NextState()
+		if next != expected || timer.State != expected {
+			t.Errorf("Expected %v next, Next said %v and NextState went to %v", expected, next, timer.State)
+		}
+	}
+	if got := timer.Completed(); got != 1 {
+		t.Errorf("Expected 1 pomodoro completed in the new cycle, got %d", got)
+	}
+}
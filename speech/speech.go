@@ -0,0 +1,91 @@
+// Package speech speaks announcements through a text-to-speech program
+// installed on the system.
+package speech
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// ErrNoEngine is returned by Find when no supported program is installed.
+var ErrNoEngine = errors.New("no speech engine found (install espeak-ng)")
+
+// DefaultRate is the speaking rate, in words per minute, used when Options
+// leaves it at 0.
+const DefaultRate = 160
+
+// Options tune the voice.
+type Options struct {
+	Rate  int    // words per minute
+	Voice string // voice or language name understood by the engine
+	Lang  string // language code used when Voice is empty, e.g. "pt"
+}
+
+// Engine speaks text aloud.
+type Engine interface {
+	// Name is the program used.
+	Name() string
+	// Speak says text and returns once it has been said.
+	Speak(text string) error
+}
+
+// command is an engine that runs a program with the text as its last
+// argument.
+type command struct {
+	name string
+	path string
+	args []string
+}
+
+func (c *command) Name() string { return c.name }
+
+func (c *command) Speak(text string) error {
+	args := append(append([]string{}, c.args...), "--", text)
+	if output, err := exec.Command(c.path, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", c.name, err, output)
+	}
+	return nil
+}
+
+// Find returns an engine for the first supported program found in PATH:
+// espeak-ng, espeak or speech-dispatcher's spd-say.
+func Find(opts Options) (Engine, error) {
+	return find(opts, exec.LookPath)
+}
+
+func find(opts Options, lookPath func(string) (string, error)) (Engine, error) {
+	rate := opts.Rate
+	if rate <= 0 {
+		rate = DefaultRate
+	}
+	voice := opts.Voice
+	if voice == "" {
+		voice = opts.Lang
+	}
+
+	for _, name := range []string{"espeak-ng", "espeak"} {
+		if path, err := lookPath(name); err == nil {
+			args := []string{"-s", strconv.Itoa(rate)}
+			if voice != "" {
+				args = append(args, "-v", voice)
+			}
+			return &command{name: name, path: path, args: args}, nil
+		}
+	}
+	if path, err := lookPath("spd-say"); err == nil {
+		// spd-say takes a rate from -100 to 100 around its default of
+		// about 175 words per minute, and waits only with --wait.
+		r := max(-100, min(100, (rate-175)*100/175))
+		args := []string{"--wait", "-r", strconv.Itoa(r)}
+		if opts.Voice != "" {
+			args = append(args, "-y", opts.Voice)
+		}
+		if opts.Lang != "" {
+			args = append(args, "-l", opts.Lang)
+		}
+		return &command{name: "spd-say", path: path, args: args}, nil
+	}
+	return nil, ErrNoEngine
+}
@@ -0,0 +1,65 @@
+package speech
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func lookPathFor(installed ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		if slices.Contains(installed, name) {
+			return "/usr/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed []string
+		opts      Options
+		engine    string
+		args      []string
+	}{
+		{
+			name:      "espeak-ng with the UI language",
+			installed: []string{"spd-say", "espeak-ng"},
+			opts:      Options{Lang: "pt"},
+			engine:    "espeak-ng",
+			args:      []string{"-s", "160", "-v", "pt"},
+		},
+		{
+			name:      "espeak with a voice",
+			installed: []string{"espeak"},
+			opts:      Options{Rate: 200, Voice: "en-us", Lang: "pt"},
+			engine:    "espeak",
+			args:      []string{"-s", "200", "-v", "en-us"},
+		},
+		{
+			name:      "spd-say with a scaled rate",
+			installed: []string{"spd-say"},
+			opts:      Options{Rate: 350, Lang: "es"},
+			engine:    "spd-say",
+			args:      []string{"--wait", "-r", "100", "-l", "es"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := find(tt.opts, lookPathFor(tt.installed...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := engine.(*command)
+			if c.name != tt.engine || !slices.Equal(c.args, tt.args) {
+				t.Errorf("Expected %s %q, got %s %q", tt.engine, tt.args, c.name, c.args)
+			}
+		})
+	}
+
+	if _, err := find(Options{}, lookPathFor()); !errors.Is(err, ErrNoEngine) {
+		t.Errorf("Expected ErrNoEngine without any program, got %v", err)
+	}
+}
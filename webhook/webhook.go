@@ -0,0 +1,247 @@
+// Package webhook POSTs timer events to configured URLs, retrying failed
+// deliveries and keeping a log of recent ones.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pomodoro-do-ben/config"
+)
+
+// Payload is what a webhook is told about a timer event. It is the request
+// body when the webhook has no body template.
+type Payload struct {
+	Event     string    `json:"event"` // as in config.WebhookEvents
+	Phase     string    `json:"phase"` // as in config.Phases
+	Next      string    `json:"next"`
+	Cycle     string    `json:"cycle"` // e.g. "2/4"
+	Task      string    `json:"task,omitempty"`
+	Remaining int       `json:"remaining"` // seconds left of the phase
+	Timestamp time.Time `json:"timestamp"`
+	// PhaseEndsAt is when the phase ends if the timer keeps running.
+	PhaseEndsAt *time.Time `json:"phase_ends_at,omitempty"`
+}
+
+// Body returns the request body of hook for p: the JSON payload, or the
+// hook's template with its placeholders replaced. Values are escaped for a
+// JSON string when the Content-Type header says JSON.
+func Body(hook config.Webhook, p Payload) ([]byte, error) {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	if hook.Body == "" {
+		return payload, nil
+	}
+	escape := func(s string) string { return s }
+	if strings.Contains(contentType(hook), "json") {
+		escape = func(s string) string {
+			quoted, _ := json.Marshal(s)
+			return string(quoted[1 : len(quoted)-1])
+		}
+	}
+	r := strings.NewReplacer(
+		"{event}", escape(p.Event),
+		"{phase}", escape(p.Phase),
+		"{next}", escape(p.Next),
+		"{cycle}", escape(p.Cycle),
+		"{task}", escape(p.Task),
+		"{remaining}", strconv.Itoa(p.Remaining),
+		"{timestamp}", p.Timestamp.Format(time.RFC3339),
+		"{payload}", string(payload),
+	)
+	return []byte(r.Replace(hook.Body)), nil
+}
+
+// contentType is the Content-Type header of hook, JSON unless it sets one.
+func contentType(hook config.Webhook) string {
+	for name, value := range hook.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return strings.ToLower(value)
+		}
+	}
+	return "application/json"
+}
+
+// Delivery is the outcome of POSTing one event to one webhook.
+type Delivery struct {
+	At       time.Time // when the last attempt was made
+	URL      string
+	Event    string
+	Attempts int
+	Status   int    // HTTP status of the last attempt; 0 if there was no response
+	Err      string // empty if the webhook accepted the event
+}
+
+func (d Delivery) String() string {
+	result := "ok"
+	if d.Err != "" {
+		result = d.Err
+	}
+	return fmt.Sprintf("%s %s %s: %s (%d attempts)", d.At.Format("15:04:05"), d.Event, d.URL, result, d.Attempts)
+}
+
+// Default settings of a Sender.
+const (
+	DefaultAttempts   = 3
+	DefaultBackoff    = 2 * time.Second
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxPending = 4 // deliveries in flight per webhook
+	logSize           = 50
+)
+
+// Sender delivers events to webhooks. Each delivery is tried up to Attempts
+// times, waiting Backoff before the second try and twice as long before each
+// one after. Send keeps at most MaxPending deliveries per webhook URL in
+// flight, retries and their waits included; events for a webhook at its limit
+// are dropped and logged as failed deliveries.
+type Sender struct {
+	Client     *http.Client
+	Attempts   int
+	Backoff    time.Duration
+	MaxPending int
+
+	mu      sync.Mutex
+	log     []Delivery     // newest last, at most logSize
+	pending map[string]int // deliveries in flight by URL
+}
+
+// NewSender returns a Sender with the default settings.
+func NewSender() *Sender {
+	return &Sender{
+		Client:     &http.Client{Timeout: DefaultTimeout},
+		Attempts:   DefaultAttempts,
+		Backoff:    DefaultBackoff,
+		MaxPending: DefaultMaxPending,
+	}
+}
+
+// Send POSTs p in the background to every hook that fires on its event.
+func (s *Sender) Send(hooks []config.Webhook, p Payload) {
+	for _, hook := range hooks {
+		if !hook.Fires(p.Event) {
+			continue
+		}
+		if !s.acquire(hook.URL) {
+			s.record(Delivery{At: time.Now(), URL: hook.URL, Event: p.Event, Err: "dropped, too many deliveries pending"})
+			continue
+		}
+		go func() {
+			defer s.release(hook.URL)
+			s.Post(hook, p)
+		}()
+	}
+}
+
+// acquire reserves a delivery to url, if it has fewer than MaxPending.
+func (s *Sender) acquire(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[url] >= max(s.MaxPending, 1) {
+		return false
+	}
+	if s.pending == nil {
+		s.pending = map[string]int{}
+	}
+	s.pending[url]++
+	return true
+}
+
+func (s *Sender) release(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[url]--; s.pending[url] == 0 {
+		delete(s.pending, url)
+	}
+}
+
+// Post POSTs p to hook, retrying on network errors, 5xx and 429 responses,
+// and logs the outcome.
+func (s *Sender) Post(hook config.Webhook, p Payload) Delivery {
+	d := Delivery{URL: hook.URL, Event: p.Event}
+	body, err := Body(hook, p)
+	if err != nil {
+		d.At, d.Err = time.Now(), err.Error()
+		s.record(d)
+		return d
+	}
+
+	wait := s.Backoff
+	for d.Attempts < max(s.Attempts, 1) {
+		if d.Attempts > 0 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+		d.Attempts++
+		d.At = time.Now()
+		var retry bool
+		d.Status, retry, err = s.post(hook, body)
+		if err == nil {
+			d.Err = ""
+			break
+		}
+		d.Err = err.Error()
+		if !retry {
+			break
+		}
+	}
+	s.record(d)
+	return d
+}
+
+// post makes one attempt and reports whether a failure is worth retrying.
+func (s *Sender) post(hook config.Webhook, body []byte) (status int, retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", contentType(hook))
+	req.Header.Set("User-Agent", "pomodoro-do-ben")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, true, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return resp.StatusCode, retry, fmt.Errorf("webhook answered %s", resp.Status)
+}
+
+func (s *Sender) record(d Delivery) {
+	if d.Err != "" {
+		log.Println("Error delivering webhook:", d)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log = append(s.log, d)
+	if len(s.log) > logSize {
+		s.log = s.log[len(s.log)-logSize:]
+	}
+}
+
+// Deliveries returns the most recent deliveries, newest first.
+func (s *Sender) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deliveries := make([]Delivery, len(s.log))
+	for i, d := range s.log {
+		deliveries[len(s.log)-1-i] = d
+	}
+	return deliveries
+}
@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pomodoro-do-ben/config"
+)
+
+// server records the requests it gets and answers with the given statuses in
+// turn, then 200.
+func server(t *testing.T, statuses ...int) (*httptest.Server, func() []*http.Request, func() [][]byte) {
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+		bodies   [][]byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		n := len(requests)
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		if n < len(statuses) {
+			w.WriteHeader(statuses[n])
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []*http.Request {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]*http.Request(nil), requests...)
+		}, func() [][]byte {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([][]byte(nil), bodies...)
+		}
+}
+
+func testSender() *Sender {
+	s := NewSender()
+	s.Backoff = time.Millisecond
+	return s
+}
+
+var payload = Payload{
+	Event:     config.EventFocusStart,
+	Phase:     config.PhaseFocus,
+	Next:      config.PhaseShortBreak,
+	Cycle:     "1/4",
+	Task:      `Write "the" report`,
+	Remaining: 1500,
+	Timestamp: time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC),
+}
+
+func TestPostPayload(t *testing.T) {
+	srv, requests, bodies := server(t)
+	hook := config.Webhook{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+
+	d := testSender().Post(hook, payload)
+	if d.Err != "" || d.Status != http.StatusOK || d.Attempts != 1 {
+		t.Fatalf("Expected one successful attempt, got %+v", d)
+	}
+	r := requests()[0]
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Unexpected request %s with headers %v", r.Method, r.Header)
+	}
+	var got Payload
+	if err := json.Unmarshal(bodies()[0], &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != payload.Event || got.Task != payload.Task || got.Remaining != 1500 || !got.Timestamp.Equal(payload.Timestamp) {
+		t.Errorf("Expected payload %+v, got %+v", payload, got)
+	}
+}
+
+func TestPostTemplate(t *testing.T) {
+	srv, _, bodies := server(t)
+	hook := config.Webhook{URL: srv.URL, Body: `{"text": "{phase} {cycle}: {task}, {remaining}s"}`}
+
+	if d := testSender().Post(hook, payload); d.Err != "" {
+		t.Fatal(d.Err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(bodies()[0], &got); err != nil {
+		t.Fatalf("Expected the task escaped for JSON, got %s: %v", bodies()[0], err)
+	}
+	if want := `focus 1/4: Write "the" report, 1500s`; got["text"] != want {
+		t.Errorf("Expected %q, got %q", want, got["text"])
+	}
+
+	hook.Body = "{task}"
+	hook.Headers = map[string]string{"content-type": "text/plain"}
+	if body, _ := Body(hook, payload); string(body) != payload.Task {
+		t.Errorf("Expected plain text left as it is, got %s", body)
+	}
+}
+
+func TestPostRetries(t *testing.T) {
+	srv, requests, _ := server(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	s := testSender()
+
+	if d := s.Post(config.Webhook{URL: srv.URL}, payload); d.Err != "" || d.Attempts != 3 {
+		t.Errorf("Expected success on the third attempt, got %+v", d)
+	}
+	if n := len(requests()); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+}
+
+func TestPostGivesUp(t *testing.T) {
+	srv, requests, _ := server(t, 500, 500, 500, 500)
+	s := testSender()
+	if d := s.Post(config.Webhook{URL: srv.URL}, payload); d.Err == "" || d.Attempts != DefaultAttempts || d.Status != 500 {
+		t.Errorf("Expected failure after %d attempts, got %+v", DefaultAttempts, d)
+	}
+
+	// Client errors are not retried.
+	srv, requests, _ = server(t, http.StatusNotFound)
+	if d := s.Post(config.Webhook{URL: srv.URL}, payload); d.Err == "" || d.Attempts != 1 || len(requests()) != 1 {
+		t.Errorf("Expected a single attempt, got %+v", d)
+	}
+
+	// Nor is a URL that cannot be requested.
+	if d := s.Post(config.Webhook{URL: "http://\x7f"}, payload); d.Err == "" || d.Attempts != 1 {
+		t.Errorf("Expected a bad URL to fail at once, got %+v", d)
+	}
+}
+
+func TestDeliveries(t *testing.T) {
+	srv, _, _ := server(t, http.StatusBadRequest)
+	s := testSender()
+	s.Post(config.Webhook{URL: srv.URL}, payload)
+	p := payload
+	p.Event = config.EventPause
+	s.Post(config.Webhook{URL: srv.URL}, p)
+
+	log := s.Deliveries()
+	if len(log) != 2 || log[0].Event != config.EventPause || log[0].Err != "" || log[1].Err == "" {
+		t.Errorf("Expected the newest delivery first, got %v", log)
+	}
+	for i := 0; i < logSize; i++ {
+		s.Post(config.Webhook{URL: srv.URL}, payload)
+	}
+	if n := len(s.Deliveries()); n != logSize {
+		t.Errorf("Expected the log capped at %d, got %d", logSize, n)
+	}
+}
+
+func TestSend(t *testing.T) {
+	srv, requests, _ := server(t)
+	s := testSender()
+	s.Send([]config.Webhook{
+		{URL: srv.URL, Events: []string{config.EventPause}},
+		{URL: srv.URL, Events: []string{config.EventFocusStart}},
+		{URL: srv.URL},
+	}, payload)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Deliveries()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := len(requests()); n != 2 {
+		t.Errorf("Expected the 2 hooks firing on %s to be called, got %d requests", payload.Event, n)
+	}
+}
+
+func TestSendLimitsPendingDeliveries(t *testing.T) {
+	release := make(chan struct{})
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	other, _, _ := server(t)
+
+	s := testSender()
+	s.MaxPending = 2
+	hooks := []config.Webhook{{URL: srv.URL}}
+	for i := 0; i < 5; i++ {
+		s.Send(hooks, payload)
+	}
+	dropped := s.Deliveries()
+	if len(dropped) != 3 {
+		t.Fatalf("Expected 3 deliveries dropped over the limit, got %v", dropped)
+	}
+	for _, d := range dropped {
+		if d.Err == "" || d.Attempts != 0 {
+			t.Errorf("Expected a dropped delivery, got %+v", d)
+		}
+	}
+
+	// Other webhooks are not held up by a busy one.
+	s.Send([]config.Webhook{{URL: other.URL}}, payload)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Deliveries()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if d := s.Deliveries()[0]; d.URL != other.URL || d.Err != "" {
+		t.Errorf("Expected the other webhook to be delivered, got %+v", d)
+	}
+
+	close(release)
+	idle := func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.pending) == 0
+	}
+	for !idle() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	if calls != 2 {
+		t.Errorf("Expected 2 requests to the busy webhook, got %d", calls)
+	}
+	mu.Unlock()
+	s.Send(hooks, payload)
+	if n := len(s.Deliveries()); n != 6 {
+		t.Errorf("Expected deliveries to resume once the pending ones finished, got %v", s.Deliveries())
+	}
+}